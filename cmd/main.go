@@ -13,7 +13,7 @@ import (
 func main() {
 	// TODO: log db requests
 	// TODO: add otel
-	// TODO: add image proxy, DL
+	// TODO: add DL
 
 	fx.New(
 		fx.Provide(
@@ -25,19 +25,30 @@ func main() {
 			handlers.NewAuth,
 			handlers.NewPlaylist,
 			handlers.NewTrack,
+			handlers.NewToken,
+			handlers.NewAdmin,
+			handlers.NewImage,
 
 			// services and infra
 			infra.NewLogger,
 			infra.NewConfig,
+			infra.NewKeySet,
 			infra.NewPostgresConnection,
+			infra.NewStorage,
 			youtube.New,
+			service.NewAccess,
+			service.NewAdmin,
 			service.NewAuth,
+			service.NewImages,
+			service.NewModeration,
 			service.NewPermission,
 			service.NewPlaylist,
+			service.NewSubmissions,
+			service.NewToken,
 			service.NewTrack,
 			service.NewUser,
 		),
-		fx.Invoke(func(auth *handlers.Auth, track *handlers.Track, playlist *handlers.Playlist) {
+		fx.Invoke(func(auth *handlers.Auth, track *handlers.Track, playlist *handlers.Playlist, token *handlers.Token, admin *handlers.Admin, image *handlers.Image) {
 			// need echo and huma to start the api
 
 			// need each of controllers, to register them, maybe i'll use hooks
@@ -17,6 +17,7 @@ require (
 	github.com/pressly/goose/v3 v3.26.0
 	github.com/telegram-mini-apps/init-data-golang v1.5.0
 	go.uber.org/zap v1.27.0
+	golang.org/x/sync v0.17.0
 	gopkg.in/telebot.v4 v4.0.0-beta.5
 	gorm.io/driver/sqlite v1.6.0
 )
@@ -75,7 +76,6 @@ require (
 	golang.org/x/exp v0.0.0-20250911091902-df9299821621 // indirect
 	golang.org/x/mod v0.28.0 // indirect
 	golang.org/x/net v0.44.0 // indirect
-	golang.org/x/sync v0.17.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 	golang.org/x/text v0.29.0 // indirect
 	golang.org/x/time v0.11.0 // indirect
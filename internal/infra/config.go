@@ -3,6 +3,9 @@ package infra
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -23,6 +26,52 @@ type Config struct {
 	AppId     int    `env:"APP_ID"`
 	BotToken  string `env:"BOT_TOKEN"`
 
+	// JwtKeysDir - папка с PEM ключами (Ed25519/RSA) для подписи JWT, имя файла - kid.
+	// Если не задана, токены подписываются HS256 с JwtSecret
+	JwtKeysDir    string   `env:"JWT_KEYS_DIR"`
+	JwtSigningKey string   `env:"JWT_SIGNING_KEY"`
+	JwtOldSecrets []string `env:"JWT_OLD_SECRETS" env-separator:","`
+	JwtIssuer     string   `env:"JWT_ISSUER" env-default:"muse-backend"`
+	JwtAudience   string   `env:"JWT_AUDIENCE" env-default:"muse"`
+
+	// AccessTokenTTL - время жизни JWT, RefreshTokenTTL - время жизни сессии без обновления
+	AccessTokenTTL  time.Duration `env:"ACCESS_TOKEN_TTL" env-default:"15m"`
+	RefreshTokenTTL time.Duration `env:"REFRESH_TOKEN_TTL" env-default:"720h"`
+
+	// ModerationCards - куда бот отправляет карточки новых треков на модерации:
+	// group - в группу плейлиста (для плейлистов без группы - в личку модераторам), dm - в личку модераторам, off - не отправлять
+	ModerationCards string `env:"MODERATION_CARDS" env-default:"group"`
+
+	// MiniAppUrl - прямая ссылка на миниапп (https://t.me/<bot>/<app>), бот добавляет к ней startapp=<id плейлиста>
+	MiniAppUrl string `env:"MINI_APP_URL"`
+
+	// SyncInterval - как часто бот сверяет участников групп с плейлистами, 0 - только по команде /sync
+	SyncInterval time.Duration `env:"SYNC_INTERVAL" env-default:"6h"`
+
+	// AdminIds - Telegram ID глобальных администраторов, им доступен /api/admin
+	AdminIds []int64 `env:"ADMIN_IDS" env-separator:","`
+
+	// Storage - где хранить обложки плейлистов: local - в папке StorageDir, раздаётся по /api/storage, s3 - в S3-совместимом бакете
+	Storage          string `env:"STORAGE" env-default:"local"`
+	StorageDir       string `env:"STORAGE_DIR" env-default:"data/storage"`
+	StoragePublicUrl string `env:"STORAGE_PUBLIC_URL" env-default:"http://localhost:8080/api/storage"`
+
+	// S3PublicUrl - откуда отдаются файлы бакета, если пусто - <S3_ENDPOINT>/<S3_BUCKET>
+	S3Endpoint  string `env:"S3_ENDPOINT" env-default:"http://localhost:9000"`
+	S3Bucket    string `env:"S3_BUCKET" env-default:"muse"`
+	S3Region    string `env:"S3_REGION" env-default:"us-east-1"`
+	S3AccessKey string `env:"S3_ACCESS_KEY"`
+	S3SecretKey string `env:"S3_SECRET_KEY"`
+	S3PublicUrl string `env:"S3_PUBLIC_URL"`
+
+	// ImageProxyUrl - откуда отдаются обложки треков через прокси (/api/images), ImageHosts - с каких хостов прокси их скачивает.
+	// Ссылки уходят и в Telegram (превью inline результатов), поэтому адрес должен быть публичным. Пустой - прокси выключен.
+	// ImageCacheSize - предел кеша картинок на диске в байтах, при превышении удаляются давно не запрашиваемые
+	ImageProxyUrl  string   `env:"IMAGE_PROXY_URL"`
+	ImageHosts     []string `env:"IMAGE_HOSTS" env-separator:"," env-default:"lh3.googleusercontent.com,i.ytimg.com,yt3.ggpht.com,yt3.googleusercontent.com"`
+	ImageCacheDir  string   `env:"IMAGE_CACHE_DIR" env-default:"data/images"`
+	ImageCacheSize int64    `env:"IMAGE_CACHE_SIZE" env-default:"536870912"`
+
 	Debug bool `env:"DEBUG" env-default:"false"`
 }
 
@@ -34,10 +83,34 @@ func NewConfig() (*Config, error) {
 
 	cfg.DbUrl = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", cfg.DbUser, cfg.DbPassword, cfg.DbHost, cfg.DbPort, cfg.DbName)
 
-	if cfg.JwtSecret == "" {
+	if cfg.JwtKeysDir == "" && cfg.JwtSecret == "" {
 		return nil, errors.New("JWT_SECRET is REQUIRED not to be null")
 	}
 
+	if cfg.JwtKeysDir != "" && cfg.JwtSigningKey == "" {
+		return nil, errors.New("JWT_SIGNING_KEY is REQUIRED when JWT_KEYS_DIR is set")
+	}
+
+	switch cfg.ModerationCards {
+	case "group", "dm", "off":
+	default:
+		return nil, errors.New("MODERATION_CARDS must be one of group, dm, off")
+	}
+
+	if cfg.ImageProxyUrl != "" && !isPublicUrl(cfg.ImageProxyUrl) {
+		return nil, errors.New("IMAGE_PROXY_URL must be an absolute public http(s) URL")
+	}
+
+	switch cfg.Storage {
+	case "local":
+	case "s3":
+		if cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+			return nil, errors.New("S3_ACCESS_KEY and S3_SECRET_KEY are REQUIRED when STORAGE=s3")
+		}
+	default:
+		return nil, errors.New("STORAGE must be one of local, s3")
+	}
+
 	if cfg.AppHash == "" {
 		return nil, errors.New("APP_HASH is REQUIRED not to be null")
 	}
@@ -52,3 +125,18 @@ func NewConfig() (*Config, error) {
 
 	return &cfg, nil
 }
+
+// isPublicUrl - абсолютная http(s) ссылка, которую может открыть Telegram: не localhost и не адрес локальной сети
+func isPublicUrl(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+
+	if u.Hostname() == "localhost" {
+		return false
+	}
+
+	ip := net.ParseIP(u.Hostname())
+	return ip == nil || !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast())
+}
@@ -1,6 +1,7 @@
 package infra
 
 import (
+	"backend/pkg/i18n"
 	"io"
 
 	"github.com/bytedance/sonic"
@@ -45,9 +46,28 @@ func defaultApiConfig() huma.Config {
 		Description: "dev",
 	})
 
+	apiCfg.Transformers = append(apiCfg.Transformers, localizeErrors)
+
 	return apiCfg
 }
 
+// localizeErrors - перевести текст ошибки на язык из Accept-Language. Без заголовка ошибки остаются на английском
+func localizeErrors(ctx huma.Context, status string, v any) (any, error) {
+	model, ok := v.(*huma.ErrorModel)
+	if !ok {
+		return v, nil
+	}
+
+	language := i18n.FromAcceptLanguage(ctx.Header("Accept-Language"))
+	if language == "" {
+		return v, nil
+	}
+
+	model.Detail = i18n.Error(language, model.Detail)
+
+	return model, nil
+}
+
 func NewHuma(echo *echo.Echo) huma.API {
 	api := humaecho.New(echo, defaultApiConfig())
 
@@ -0,0 +1,160 @@
+package infra
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey - ключ подписи JWT. Для HS256 Sign и Verify - один и тот же секрет, Public - nil
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Sign   any
+	Verify any
+	Public crypto.PublicKey
+}
+
+// KeySet - ключи для проверки JWT, одним из них (активным) подписываются новые токены
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+/*
+NewKeySet - загрузить ключи подписи.
+
+Если задан JWT_KEYS_DIR - берутся все PEM ключи (Ed25519 или RSA) из папки, kid - имя файла без расширения,
+активный ключ задаётся JWT_SIGNING_KEY. Иначе - HS256 с JWT_SECRET, старые секреты из JWT_OLD_SECRETS
+продолжают приниматься, пока не истекут выданные ими токены
+*/
+func NewKeySet(cfg *Config) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*SigningKey)}
+
+	if cfg.JwtKeysDir == "" {
+		for i, secret := range append([]string{cfg.JwtSecret}, cfg.JwtOldSecrets...) {
+			key := hmacKey(secret)
+			set.keys[key.ID] = key
+			if i == 0 {
+				set.active = key
+			}
+		}
+
+		return set, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(cfg.JwtKeysDir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		key, err := loadPemKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt key %s: %w", file, err)
+		}
+		set.keys[key.ID] = key
+	}
+
+	active, ok := set.keys[cfg.JwtSigningKey]
+	if !ok {
+		return nil, errors.New("JWT_SIGNING_KEY " + cfg.JwtSigningKey + " not found in " + cfg.JwtKeysDir)
+	}
+	set.active = active
+
+	return set, nil
+}
+
+// Active - ключ, которым подписываются новые токены
+func (s *KeySet) Active() *SigningKey {
+	return s.active
+}
+
+// Get - найти ключ по kid
+func (s *KeySet) Get(id string) (*SigningKey, bool) {
+	key, ok := s.keys[id]
+	return key, ok
+}
+
+// Public - асимметричные ключи, которые можно опубликовать в JWKS
+func (s *KeySet) Public() []*SigningKey {
+	result := make([]*SigningKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		if key.Public != nil {
+			result = append(result, key)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+func hmacKey(secret string) *SigningKey {
+	sum := sha256.Sum256([]byte(secret))
+
+	return &SigningKey{
+		ID:     "hs-" + hex.EncodeToString(sum[:4]),
+		Method: jwt.SigningMethodHS256,
+		Sign:   []byte(secret),
+		Verify: []byte(secret),
+	}
+}
+
+func loadPemKey(file string) (*SigningKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var private any
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, errors.New("unsupported PEM block " + block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{
+		ID:   strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)),
+		Sign: private,
+	}
+
+	switch k := private.(type) {
+	case ed25519.PrivateKey:
+		key.Method = jwt.SigningMethodEdDSA
+		key.Verify = k.Public()
+	case *rsa.PrivateKey:
+		key.Method = jwt.SigningMethodRS256
+		key.Verify = &k.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", private)
+	}
+	key.Public = key.Verify
+
+	return key, nil
+}
@@ -97,6 +97,30 @@ func (ns NullPlaylistType) Value() (driver.Value, error) {
 	return string(ns.PlaylistType), nil
 }
 
+type ApiToken struct {
+	ID         string
+	UserID     int64
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	Playlists  []string
+	CreatedAt  pgtype.Timestamptz
+	LastUsedAt pgtype.Timestamptz
+	ExpiresAt  pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+}
+
+type ModerationLog struct {
+	ID           int64
+	PlaylistID   string
+	ActorID      int64
+	Action       string
+	TargetUserID pgtype.Int8
+	TrackID      pgtype.Text
+	Details      string
+	CreatedAt    pgtype.Timestamptz
+}
+
 type Playlist struct {
 	ID            string
 	Title         string
@@ -109,12 +133,42 @@ type Playlist struct {
 	Count         pgtype.Int4
 	AllowedCount  pgtype.Int4
 	Time          int32
+	DeletedAt     pgtype.Timestamptz
+	Language      string
+	TopicID       int32
+}
+
+type PlaylistMute struct {
+	PlaylistID string
+	UserID     int64
+	MutedBy    int64
+	Reason     string
+	ExpiresAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
+type PlaylistOverride struct {
+	PlaylistID string
+	Role       PlaylistRole
+	Capability string
+	Allowed    bool
 }
 
 type PlaylistPermission struct {
 	PlaylistID string
 	UserID int64
 	Role   PlaylistRole
+	CreatedAt  pgtype.Timestamptz
+}
+
+type Session struct {
+	ID           string
+	UserID       int64
+	RefreshHash  string
+	PreviousHash pgtype.Text
+	ExpiresAt    pgtype.Timestamptz
+	CreatedAt    pgtype.Timestamptz
+	RevokedAt    pgtype.Timestamptz
 }
 
 type Track struct {
@@ -126,6 +180,19 @@ type Track struct {
 	Explicit  bool
 }
 
+type TrackSubmission struct {
+	PlaylistID string
+	TrackID    string
+	UserID     int64
+	CreatedAt  pgtype.Timestamptz
+}
+
 type User struct {
-	ID int64
+	ID           int64
+	FirstName    string
+	LastName     string
+	Username     string
+	LanguageCode string
+	PhotoUrl     string
+	UpdatedAt    pgtype.Timestamptz
 }
@@ -11,9 +11,184 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addSubmission = `-- name: AddSubmission :exec
+INSERT INTO track_submissions (playlist_id, track_id, user_id, submitter_name)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (playlist_id, track_id) DO UPDATE SET user_id = EXCLUDED.user_id, submitter_name = EXCLUDED.submitter_name, created_at = now()
+`
+
+type AddSubmissionParams struct {
+	PlaylistID    string
+	TrackID       string
+	UserID        int64
+	SubmitterName string
+}
+
+func (q *Queries) AddSubmission(ctx context.Context, arg AddSubmissionParams) error {
+	_, err := q.db.Exec(ctx, addSubmission,
+		arg.PlaylistID,
+		arg.TrackID,
+		arg.UserID,
+		arg.SubmitterName,
+	)
+	return err
+}
+
+const adminSearchPlaylists = `-- name: AdminSearchPlaylists :many
+SELECT
+    pl.id, pl.title, pl.thumbnail, pl.type, pl.external_id, pl.telegram_id, pl.tracks, pl.allowed_tracks, pl.count, pl.allowed_count, pl.time, pl.deleted_at, pl.language, pl.topic_id,
+    COALESCE((SELECT p.user_id FROM playlist_permissions p WHERE p.playlist_id = pl.id AND p.role = 'owner'), 0)::bigint AS owner_id
+FROM playlists pl
+WHERE ($1::text = ''
+    OR pl.id = $1::text
+    OR pl.telegram_id::text = $1::text
+    OR pl.title ILIKE '%' || $1::text || '%')
+  AND (pl.deleted_at IS NOT NULL) = $2::bool
+ORDER BY pl.id DESC
+LIMIT $3 OFFSET $4
+`
+
+type AdminSearchPlaylistsParams struct {
+	Query   string
+	Deleted bool
+	Lim     int32
+	Off     int32
+}
+
+type AdminSearchPlaylistsRow struct {
+	ID            string
+	Title         string
+	Thumbnail     string
+	Type          PlaylistType
+	ExternalID    pgtype.Text
+	TelegramID    pgtype.Int8
+	Tracks        []string
+	AllowedTracks []string
+	Count         pgtype.Int4
+	AllowedCount  pgtype.Int4
+	Time          int32
+	DeletedAt     pgtype.Timestamptz
+	Language      string
+	TopicID       int32
+	OwnerID       int64
+}
+
+func (q *Queries) AdminSearchPlaylists(ctx context.Context, arg AdminSearchPlaylistsParams) ([]AdminSearchPlaylistsRow, error) {
+	rows, err := q.db.Query(ctx, adminSearchPlaylists,
+		arg.Query,
+		arg.Deleted,
+		arg.Lim,
+		arg.Off,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AdminSearchPlaylistsRow
+	for rows.Next() {
+		var i AdminSearchPlaylistsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Thumbnail,
+			&i.Type,
+			&i.ExternalID,
+			&i.TelegramID,
+			&i.Tracks,
+			&i.AllowedTracks,
+			&i.Count,
+			&i.AllowedCount,
+			&i.Time,
+			&i.DeletedAt,
+			&i.Language,
+			&i.TopicID,
+			&i.OwnerID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const adminSearchUsers = `-- name: AdminSearchUsers :many
+SELECT id, first_name, last_name, username, language_code, photo_url, updated_at FROM users
+WHERE $1::text = ''
+   OR id::text = $1::text
+   OR username ILIKE '%' || $1::text || '%'
+   OR (first_name || ' ' || last_name) ILIKE '%' || $1::text || '%'
+ORDER BY id
+LIMIT $2 OFFSET $3
+`
+
+type AdminSearchUsersParams struct {
+	Query string
+	Lim   int32
+	Off   int32
+}
+
+func (q *Queries) AdminSearchUsers(ctx context.Context, arg AdminSearchUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, adminSearchUsers, arg.Query, arg.Lim, arg.Off)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Username,
+			&i.LanguageCode,
+			&i.PhotoUrl,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createApiToken = `-- name: CreateApiToken :exec
+INSERT INTO api_tokens (id, user_id, name, token_hash, scopes, playlists, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreateApiTokenParams struct {
+	ID        string
+	UserID    int64
+	Name      string
+	TokenHash string
+	Scopes    []string
+	Playlists []string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateApiToken(ctx context.Context, arg CreateApiTokenParams) error {
+	_, err := q.db.Exec(ctx, createApiToken,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		arg.Scopes,
+		arg.Playlists,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
 const createPlaylist = `-- name: CreatePlaylist :exec
-INSERT INTO playlists (id, title, thumbnail, tracks, allowed_tracks, type, external_id, telegram_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO playlists (id, title, thumbnail, tracks, allowed_tracks, type, external_id, telegram_id, topic_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 `
 
 type CreatePlaylistParams struct {
@@ -25,6 +200,7 @@ type CreatePlaylistParams struct {
 	Type          PlaylistType
 	ExternalID    string
 	TelegramID    int64
+	TopicID       int32
 }
 
 func (q *Queries) CreatePlaylist(ctx context.Context, arg CreatePlaylistParams) error {
@@ -37,6 +213,7 @@ func (q *Queries) CreatePlaylist(ctx context.Context, arg CreatePlaylistParams)
 		arg.Type,
 		arg.ExternalID,
 		arg.TelegramID,
+		arg.TopicID,
 	)
 	return err
 }
@@ -57,6 +234,28 @@ func (q *Queries) CreateRole(ctx context.Context, arg CreateRoleParams) error {
 	return err
 }
 
+const createSession = `-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, refresh_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateSessionParams struct {
+	ID          string
+	UserID      int64
+	RefreshHash string
+	ExpiresAt   pgtype.Timestamptz
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	_, err := q.db.Exec(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.RefreshHash,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
 const createTrack = `-- name: CreateTrack :exec
 INSERT INTO tracks (id, title, authors, thumbnail, length, explicit)
 VALUES ($1, $2, $3, $4, $5, $6)
@@ -92,6 +291,15 @@ func (q *Queries) CreateUser(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteExpiredSessions = `-- name: DeleteExpiredSessions :exec
+DELETE FROM sessions WHERE user_id = $1 AND expires_at < now()
+`
+
+func (q *Queries) DeleteExpiredSessions(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, deleteExpiredSessions, userID)
+	return err
+}
+
 const deletePlaylist = `-- name: DeletePlaylist :exec
 DELETE FROM playlists WHERE id = $1
 `
@@ -101,6 +309,21 @@ func (q *Queries) DeletePlaylist(ctx context.Context, id string) error {
 	return err
 }
 
+const deletePlaylistOverride = `-- name: DeletePlaylistOverride :exec
+DELETE FROM playlist_overrides WHERE playlist_id = $1 AND role = $2 AND capability = $3
+`
+
+type DeletePlaylistOverrideParams struct {
+	PlaylistID string
+	Role       PlaylistRole
+	Capability string
+}
+
+func (q *Queries) DeletePlaylistOverride(ctx context.Context, arg DeletePlaylistOverrideParams) error {
+	_, err := q.db.Exec(ctx, deletePlaylistOverride, arg.PlaylistID, arg.Role, arg.Capability)
+	return err
+}
+
 const deleteRole = `-- name: DeleteRole :exec
 DELETE FROM playlist_permissions
 WHERE playlist_id = $1 AND user_id = $2
@@ -116,33 +339,48 @@ func (q *Queries) DeleteRole(ctx context.Context, arg DeleteRoleParams) error {
 	return err
 }
 
+const demoteOwner = `-- name: DemoteOwner :exec
+UPDATE playlist_permissions
+SET role = 'moderator'
+WHERE playlist_id = $1 AND role = 'owner' AND user_id <> $2
+`
+
+type DemoteOwnerParams struct {
+	PlaylistID string
+	UserID     int64
+}
+
+func (q *Queries) DemoteOwner(ctx context.Context, arg DemoteOwnerParams) error {
+	_, err := q.db.Exec(ctx, demoteOwner, arg.PlaylistID, arg.UserID)
+	return err
+}
+
 const editPlaylist = `-- name: EditPlaylist :exec
 UPDATE playlists
 SET
-    title = COALESCE($2, title),
-    thumbnail = COALESCE($3, thumbnail),
-    tracks = COALESCE($4, tracks),
-    allowed_tracks = COALESCE($5, allowed_tracks),
-    type = COALESCE($6, type),
-    external_id = COALESCE($7, external_id),
-    telegram_id = COALESCE($8, external_id)
-WHERE id = $1
+    title = COALESCE($1, title),
+    thumbnail = COALESCE($2, thumbnail),
+    tracks = COALESCE($3, tracks),
+    allowed_tracks = COALESCE($4, allowed_tracks),
+    type = COALESCE($5, type),
+    external_id = COALESCE($6, external_id),
+    telegram_id = COALESCE($7, telegram_id)
+WHERE id = $8
 `
 
 type EditPlaylistParams struct {
-	ID            string
-	Title         string
-	Thumbnail     string
+	Title         pgtype.Text
+	Thumbnail     pgtype.Text
 	Tracks        []string
 	AllowedTracks []string
-	Type          PlaylistType
-	ExternalID    string
-	TelegramID    int64
+	Type          NullPlaylistType
+	ExternalID    pgtype.Text
+	TelegramID    pgtype.Int8
+	ID            string
 }
 
 func (q *Queries) EditPlaylist(ctx context.Context, arg EditPlaylistParams) error {
 	_, err := q.db.Exec(ctx, editPlaylist,
-		arg.ID,
 		arg.Title,
 		arg.Thumbnail,
 		arg.Tracks,
@@ -150,6 +388,7 @@ func (q *Queries) EditPlaylist(ctx context.Context, arg EditPlaylistParams) erro
 		arg.Type,
 		arg.ExternalID,
 		arg.TelegramID,
+		arg.ID,
 	)
 	return err
 }
@@ -171,85 +410,60 @@ func (q *Queries) EditRole(ctx context.Context, arg EditRoleParams) error {
 	return err
 }
 
-const getGroupPlaylist = `-- name: GetGroupPlaylist :one
-SELECT
-    id, title, thumbnail, type, external_id, telegram_id, tracks, allowed_tracks, count, allowed_count, time
-FROM playlists
-WHERE telegram_id = $1
+const getActiveMute = `-- name: GetActiveMute :one
+SELECT playlist_id, user_id, muted_by, reason, expires_at, created_at FROM playlist_mutes
+WHERE playlist_id = $1 AND user_id = $2 AND (expires_at IS NULL OR expires_at > now())
 `
 
-func (q *Queries) GetGroupPlaylist(ctx context.Context, telegramID int64) (Playlist, error) {
-	row := q.db.QueryRow(ctx, getGroupPlaylist, telegramID)
-	var i Playlist
+type GetActiveMuteParams struct {
+	PlaylistID string
+	UserID     int64
+}
+
+func (q *Queries) GetActiveMute(ctx context.Context, arg GetActiveMuteParams) (PlaylistMute, error) {
+	row := q.db.QueryRow(ctx, getActiveMute, arg.PlaylistID, arg.UserID)
+	var i PlaylistMute
 	err := row.Scan(
-		&i.ID,
-		&i.Title,
-		&i.Thumbnail,
-		&i.Type,
-		&i.ExternalID,
-		&i.TelegramID,
-		&i.Tracks,
-		&i.AllowedTracks,
-		&i.Count,
-		&i.AllowedCount,
-		&i.Time,
+		&i.PlaylistID,
+		&i.UserID,
+		&i.MutedBy,
+		&i.Reason,
+		&i.ExpiresAt,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
-const getRole = `-- name: GetRole :one
-SELECT playlist_id FROM playlist_permissions
-WHERE user_id = $1 AND role = $2
-`
-
-type GetRoleParams struct {
-	UserID int64
-	Role   PlaylistRole
-}
-
-func (q *Queries) GetRole(ctx context.Context, arg GetRoleParams) (string, error) {
-	row := q.db.QueryRow(ctx, getRole, arg.UserID, arg.Role)
-	var playlist_id string
-	err := row.Scan(&playlist_id)
-	return playlist_id, err
-}
-
-const getTrackById = `-- name: GetTrackById :one
-SELECT id, title, authors, thumbnail, length, explicit FROM tracks WHERE id = $1
+const getApiTokenByHash = `-- name: GetApiTokenByHash :one
+SELECT id, user_id, name, token_hash, scopes, playlists, created_at, last_used_at, expires_at, revoked_at FROM api_tokens WHERE token_hash = $1
 `
 
-func (q *Queries) GetTrackById(ctx context.Context, id string) (Track, error) {
-	row := q.db.QueryRow(ctx, getTrackById, id)
-	var i Track
+func (q *Queries) GetApiTokenByHash(ctx context.Context, tokenHash string) (ApiToken, error) {
+	row := q.db.QueryRow(ctx, getApiTokenByHash, tokenHash)
+	var i ApiToken
 	err := row.Scan(
 		&i.ID,
-		&i.Title,
-		&i.Authors,
-		&i.Thumbnail,
-		&i.Length,
-		&i.Explicit,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.Playlists,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
 	)
 	return i, err
 }
 
-const getTrackPlaylists = `-- name: GetTrackPlaylists :many
-SELECT pl.id
-FROM playlists pl
-         JOIN playlist_permissions pp ON pl.id = pp.playlist_id
-WHERE
-    pp.user_id = $1
-  AND $2::text = ANY(pl.tracks)
+const getChatPlaylists = `-- name: GetChatPlaylists :many
+SELECT id FROM playlists
+WHERE telegram_id = $1 AND deleted_at IS NULL
+ORDER BY topic_id
 `
 
-type GetTrackPlaylistsParams struct {
-	UserID  int64
-	TrackID string
-}
-
-// param: TrackId text
-// param: UserId bigint
-func (q *Queries) GetTrackPlaylists(ctx context.Context, arg GetTrackPlaylistsParams) ([]string, error) {
-	rows, err := q.db.Query(ctx, getTrackPlaylists, arg.UserID, arg.TrackID)
+func (q *Queries) GetChatPlaylists(ctx context.Context, telegramID int64) ([]string, error) {
+	rows, err := q.db.Query(ctx, getChatPlaylists, telegramID)
 	if err != nil {
 		return nil, err
 	}
@@ -268,49 +482,42 @@ func (q *Queries) GetTrackPlaylists(ctx context.Context, arg GetTrackPlaylistsPa
 	return items, nil
 }
 
-const getUserById = `-- name: GetUserById :one
-SELECT id FROM users WHERE id = $1
+const getGroupLanguage = `-- name: GetGroupLanguage :one
+SELECT language FROM playlists
+WHERE telegram_id = $1 AND topic_id IN (0, $2::int) AND deleted_at IS NULL
+ORDER BY topic_id DESC
+LIMIT 1
 `
 
-func (q *Queries) GetUserById(ctx context.Context, id int64) (int64, error) {
-	row := q.db.QueryRow(ctx, getUserById, id)
-	err := row.Scan(&id)
-	return id, err
+type GetGroupLanguageParams struct {
+	TelegramID int64
+	TopicID    int32
 }
 
-const getUserPlaylistById = `-- name: GetUserPlaylistById :one
+func (q *Queries) GetGroupLanguage(ctx context.Context, arg GetGroupLanguageParams) (string, error) {
+	row := q.db.QueryRow(ctx, getGroupLanguage, arg.TelegramID, arg.TopicID)
+	var language string
+	err := row.Scan(&language)
+	return language, err
+}
+
+const getGroupPlaylist = `-- name: GetGroupPlaylist :one
 SELECT
-    pl.id, pl.title, pl.thumbnail, pl.type, pl.external_id, pl.telegram_id, pl.tracks, pl.allowed_tracks, pl.count, pl.allowed_count, pl.time,
-    p.role
-FROM playlist_permissions p
-         JOIN playlists pl ON p.playlist_id = pl.id
-         JOIN users u ON p.user_id = u.id  -- Join users table
-WHERE p.playlist_id = $1 AND  p.user_id = $2
+    id, title, thumbnail, type, external_id, telegram_id, tracks, allowed_tracks, count, allowed_count, time, deleted_at, language, topic_id
+FROM playlists
+WHERE telegram_id = $1 AND topic_id IN (0, $2::int) AND deleted_at IS NULL
+ORDER BY topic_id DESC
+LIMIT 1
 `
 
-type GetUserPlaylistByIdParams struct {
-	PlaylistID string
-	UserID     int64
-}
-
-type GetUserPlaylistByIdRow struct {
-	ID            string
-	Title         string
-	Thumbnail  string
-	Type       PlaylistType
-	ExternalID string
-	TelegramID    int64
-	Tracks        []string
-	AllowedTracks []string
-	Count         pgtype.Int4
-	AllowedCount  pgtype.Int4
-	Time       int32
-	Role       PlaylistRole
+type GetGroupPlaylistParams struct {
+	TelegramID int64
+	TopicID    int32
 }
 
-func (q *Queries) GetUserPlaylistById(ctx context.Context, arg GetUserPlaylistByIdParams) (GetUserPlaylistByIdRow, error) {
-	row := q.db.QueryRow(ctx, getUserPlaylistById, arg.PlaylistID, arg.UserID)
-	var i GetUserPlaylistByIdRow
+func (q *Queries) GetGroupPlaylist(ctx context.Context, arg GetGroupPlaylistParams) (Playlist, error) {
+	row := q.db.QueryRow(ctx, getGroupPlaylist, arg.TelegramID, arg.TopicID)
+	var i Playlist
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
@@ -323,46 +530,640 @@ func (q *Queries) GetUserPlaylistById(ctx context.Context, arg GetUserPlaylistBy
 		&i.Count,
 		&i.AllowedCount,
 		&i.Time,
-		&i.Role,
+		&i.DeletedAt,
+		&i.Language,
+		&i.TopicID,
 	)
 	return i, err
 }
 
-const getUserPlaylists = `-- name: GetUserPlaylists :many
-SELECT
-    pl.id, pl.title, pl.thumbnail, pl.type, pl.external_id, pl.telegram_id, pl.tracks, pl.allowed_tracks, pl.count, pl.allowed_count, pl.time,
-    p.role
-FROM playlists pl
-         JOIN playlist_permissions p ON pl.id = p.playlist_id
-         JOIN users u ON p.user_id = u.id  -- Join users table
-WHERE p.user_id = $1
+const getGroupPlaylistConflict = `-- name: GetGroupPlaylistConflict :one
+SELECT live.id FROM playlists deleted
+JOIN playlists live ON live.telegram_id = deleted.telegram_id AND live.topic_id = deleted.topic_id
+WHERE deleted.id = $1 AND deleted.telegram_id <> 0 AND live.deleted_at IS NULL AND live.id <> deleted.id
+LIMIT 1
 `
 
-type GetUserPlaylistsRow struct {
-	ID            string
-	Title         string
-	Thumbnail  string
-	Type       PlaylistType
-	ExternalID string
-	TelegramID    int64
-	Tracks        []string
-	AllowedTracks []string
-	Count         pgtype.Int4
-	AllowedCount  pgtype.Int4
-	Time       int32
-	Role       PlaylistRole
+func (q *Queries) GetGroupPlaylistConflict(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRow(ctx, getGroupPlaylistConflict, id)
+	err := row.Scan(&id)
+	return id, err
 }
 
-func (q *Queries) GetUserPlaylists(ctx context.Context, userID int64) ([]GetUserPlaylistsRow, error) {
-	rows, err := q.db.Query(ctx, getUserPlaylists, userID)
+const getGroupPlaylists = `-- name: GetGroupPlaylists :many
+SELECT id, telegram_id FROM playlists
+WHERE telegram_id <> 0 AND deleted_at IS NULL
+ORDER BY id
+`
+
+type GetGroupPlaylistsRow struct {
+	ID         string
+	TelegramID int64
+}
+
+func (q *Queries) GetGroupPlaylists(ctx context.Context) ([]GetGroupPlaylistsRow, error) {
+	rows, err := q.db.Query(ctx, getGroupPlaylists)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUserPlaylistsRow
+	var items []GetGroupPlaylistsRow
 	for rows.Next() {
-		var i GetUserPlaylistsRow
-		if err := rows.Scan(
+		var i GetGroupPlaylistsRow
+		if err := rows.Scan(&i.ID, &i.TelegramID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOwnerSuccessor = `-- name: GetOwnerSuccessor :one
+SELECT user_id FROM playlist_permissions
+WHERE playlist_id = $1 AND role = 'moderator'
+ORDER BY created_at, user_id
+LIMIT 1
+`
+
+func (q *Queries) GetOwnerSuccessor(ctx context.Context, playlistID string) (int64, error) {
+	row := q.db.QueryRow(ctx, getOwnerSuccessor, playlistID)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getPlaylistById = `-- name: GetPlaylistById :one
+SELECT id, title, thumbnail, type, external_id, telegram_id, tracks, allowed_tracks, count, allowed_count, time, deleted_at, language, topic_id FROM playlists WHERE id = $1
+`
+
+func (q *Queries) GetPlaylistById(ctx context.Context, id string) (Playlist, error) {
+	row := q.db.QueryRow(ctx, getPlaylistById, id)
+	var i Playlist
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Thumbnail,
+		&i.Type,
+		&i.ExternalID,
+		&i.TelegramID,
+		&i.Tracks,
+		&i.AllowedTracks,
+		&i.Count,
+		&i.AllowedCount,
+		&i.Time,
+		&i.DeletedAt,
+		&i.Language,
+		&i.TopicID,
+	)
+	return i, err
+}
+
+const getPlaylistHistory = `-- name: GetPlaylistHistory :many
+SELECT id, playlist_id, actor_id, action, target_user_id, track_id, details, created_at FROM moderation_log
+WHERE playlist_id = $1
+ORDER BY id DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetPlaylistHistoryParams struct {
+	PlaylistID string
+	Limit      int32
+	Offset     int32
+}
+
+func (q *Queries) GetPlaylistHistory(ctx context.Context, arg GetPlaylistHistoryParams) ([]ModerationLog, error) {
+	rows, err := q.db.Query(ctx, getPlaylistHistory, arg.PlaylistID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ModerationLog
+	for rows.Next() {
+		var i ModerationLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlaylistID,
+			&i.ActorID,
+			&i.Action,
+			&i.TargetUserID,
+			&i.TrackID,
+			&i.Details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlaylistMembers = `-- name: GetPlaylistMembers :many
+SELECT
+    u.id, u.first_name, u.last_name, u.username, u.language_code, u.photo_url, u.updated_at,
+    p.role
+FROM playlist_permissions p
+         JOIN users u ON p.user_id = u.id
+WHERE p.playlist_id = $1
+ORDER BY p.role DESC, u.id
+`
+
+type GetPlaylistMembersRow struct {
+	ID           int64
+	FirstName    string
+	LastName     string
+	Username     string
+	LanguageCode string
+	PhotoUrl     string
+	UpdatedAt    pgtype.Timestamptz
+	Role         PlaylistRole
+}
+
+func (q *Queries) GetPlaylistMembers(ctx context.Context, playlistID string) ([]GetPlaylistMembersRow, error) {
+	rows, err := q.db.Query(ctx, getPlaylistMembers, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlaylistMembersRow
+	for rows.Next() {
+		var i GetPlaylistMembersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Username,
+			&i.LanguageCode,
+			&i.PhotoUrl,
+			&i.UpdatedAt,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlaylistMutes = `-- name: GetPlaylistMutes :many
+SELECT
+    m.playlist_id, m.user_id, m.muted_by, m.reason, m.expires_at, m.created_at,
+    u.first_name,
+    u.last_name,
+    u.username
+FROM playlist_mutes m
+         JOIN users u ON m.user_id = u.id
+WHERE m.playlist_id = $1 AND (m.expires_at IS NULL OR m.expires_at > now())
+ORDER BY m.created_at
+`
+
+type GetPlaylistMutesRow struct {
+	PlaylistID string
+	UserID     int64
+	MutedBy    int64
+	Reason     string
+	ExpiresAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+	FirstName  string
+	LastName   string
+	Username   string
+}
+
+func (q *Queries) GetPlaylistMutes(ctx context.Context, playlistID string) ([]GetPlaylistMutesRow, error) {
+	rows, err := q.db.Query(ctx, getPlaylistMutes, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlaylistMutesRow
+	for rows.Next() {
+		var i GetPlaylistMutesRow
+		if err := rows.Scan(
+			&i.PlaylistID,
+			&i.UserID,
+			&i.MutedBy,
+			&i.Reason,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.FirstName,
+			&i.LastName,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlaylistOverrides = `-- name: GetPlaylistOverrides :many
+SELECT playlist_id, role, capability, allowed FROM playlist_overrides WHERE playlist_id = $1 ORDER BY role, capability
+`
+
+func (q *Queries) GetPlaylistOverrides(ctx context.Context, playlistID string) ([]PlaylistOverride, error) {
+	rows, err := q.db.Query(ctx, getPlaylistOverrides, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlaylistOverride
+	for rows.Next() {
+		var i PlaylistOverride
+		if err := rows.Scan(
+			&i.PlaylistID,
+			&i.Role,
+			&i.Capability,
+			&i.Allowed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlaylistTracks = `-- name: GetPlaylistTracks :many
+SELECT
+    t.id, t.title, t.authors, t.thumbnail, t.length, t.explicit,
+    COALESCE(s.user_id, 0)::bigint AS submitter_id,
+    COALESCE(s.submitter_name, '')::text AS submitter_name,
+    COALESCE(u.first_name, '')::text AS first_name,
+    COALESCE(u.last_name, '')::text AS last_name,
+    COALESCE(u.username, '')::text AS username
+FROM playlists pl
+         CROSS JOIN unnest(pl.tracks) WITH ORDINALITY AS pt(track_id, ord)
+         JOIN tracks t ON t.id = pt.track_id
+         LEFT JOIN track_submissions s ON s.playlist_id = pl.id AND s.track_id = t.id
+         LEFT JOIN users u ON u.id = s.user_id
+WHERE pl.id = $1
+ORDER BY pt.ord
+`
+
+type GetPlaylistTracksRow struct {
+	ID            string
+	Title         string
+	Authors       string
+	Thumbnail     string
+	Length        int32
+	Explicit      bool
+	SubmitterID   int64
+	SubmitterName string
+	FirstName     string
+	LastName      string
+	Username      string
+}
+
+func (q *Queries) GetPlaylistTracks(ctx context.Context, id string) ([]GetPlaylistTracksRow, error) {
+	rows, err := q.db.Query(ctx, getPlaylistTracks, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlaylistTracksRow
+	for rows.Next() {
+		var i GetPlaylistTracksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Authors,
+			&i.Thumbnail,
+			&i.Length,
+			&i.Explicit,
+			&i.SubmitterID,
+			&i.SubmitterName,
+			&i.FirstName,
+			&i.LastName,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRole = `-- name: GetRole :one
+SELECT playlist_id FROM playlist_permissions
+WHERE user_id = $1 AND role = $2
+`
+
+type GetRoleParams struct {
+	UserID int64
+	Role   PlaylistRole
+}
+
+func (q *Queries) GetRole(ctx context.Context, arg GetRoleParams) (string, error) {
+	row := q.db.QueryRow(ctx, getRole, arg.UserID, arg.Role)
+	var playlist_id string
+	err := row.Scan(&playlist_id)
+	return playlist_id, err
+}
+
+const getSession = `-- name: GetSession :one
+SELECT id, user_id, refresh_hash, previous_hash, expires_at, created_at, revoked_at FROM sessions WHERE id = $1
+`
+
+func (q *Queries) GetSession(ctx context.Context, id string) (Session, error) {
+	row := q.db.QueryRow(ctx, getSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RefreshHash,
+		&i.PreviousHash,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getSessionByRefresh = `-- name: GetSessionByRefresh :one
+SELECT id, user_id, refresh_hash, previous_hash, expires_at, created_at, revoked_at FROM sessions
+WHERE refresh_hash = $1 OR previous_hash = $1
+`
+
+func (q *Queries) GetSessionByRefresh(ctx context.Context, hash string) (Session, error) {
+	row := q.db.QueryRow(ctx, getSessionByRefresh, hash)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RefreshHash,
+		&i.PreviousHash,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getThumbnailByHash = `-- name: GetThumbnailByHash :one
+SELECT thumbnail FROM tracks
+WHERE thumbnail_hash(thumbnail) = $1::text
+LIMIT 1
+`
+
+func (q *Queries) GetThumbnailByHash(ctx context.Context, hash string) (string, error) {
+	row := q.db.QueryRow(ctx, getThumbnailByHash, hash)
+	var thumbnail string
+	err := row.Scan(&thumbnail)
+	return thumbnail, err
+}
+
+const getTrackById = `-- name: GetTrackById :one
+SELECT id, title, authors, thumbnail, length, explicit FROM tracks WHERE id = $1
+`
+
+func (q *Queries) GetTrackById(ctx context.Context, id string) (Track, error) {
+	row := q.db.QueryRow(ctx, getTrackById, id)
+	var i Track
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Authors,
+		&i.Thumbnail,
+		&i.Length,
+		&i.Explicit,
+	)
+	return i, err
+}
+
+const getTrackPlaylists = `-- name: GetTrackPlaylists :many
+SELECT pl.id
+FROM playlists pl
+         JOIN playlist_permissions pp ON pl.id = pp.playlist_id
+WHERE
+    pp.user_id = $1
+  AND $2::text = ANY(pl.tracks)
+  AND pl.deleted_at IS NULL
+`
+
+type GetTrackPlaylistsParams struct {
+	UserID  int64
+	TrackID string
+}
+
+// param: TrackId text
+// param: UserId bigint
+func (q *Queries) GetTrackPlaylists(ctx context.Context, arg GetTrackPlaylistsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, getTrackPlaylists, arg.UserID, arg.TrackID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTracksByIds = `-- name: GetTracksByIds :many
+SELECT id, title, authors, thumbnail, length, explicit FROM tracks WHERE id = ANY($1::text[])
+`
+
+func (q *Queries) GetTracksByIds(ctx context.Context, ids []string) ([]Track, error) {
+	rows, err := q.db.Query(ctx, getTracksByIds, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Track
+	for rows.Next() {
+		var i Track
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Authors,
+			&i.Thumbnail,
+			&i.Length,
+			&i.Explicit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserApiTokens = `-- name: GetUserApiTokens :many
+SELECT id, user_id, name, token_hash, scopes, playlists, created_at, last_used_at, expires_at, revoked_at FROM api_tokens
+WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+ORDER BY created_at
+`
+
+func (q *Queries) GetUserApiTokens(ctx context.Context, userID int64) ([]ApiToken, error) {
+	rows, err := q.db.Query(ctx, getUserApiTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApiToken
+	for rows.Next() {
+		var i ApiToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.TokenHash,
+			&i.Scopes,
+			&i.Playlists,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserById = `-- name: GetUserById :one
+SELECT id, first_name, last_name, username, language_code, photo_url, updated_at FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUserById(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, getUserById, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Username,
+		&i.LanguageCode,
+		&i.PhotoUrl,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserPlaylistById = `-- name: GetUserPlaylistById :one
+SELECT
+    pl.id, pl.title, pl.thumbnail, pl.type, pl.external_id, pl.telegram_id, pl.tracks, pl.allowed_tracks, pl.count, pl.allowed_count, pl.time, pl.deleted_at, pl.language, pl.topic_id,
+    p.role
+FROM playlist_permissions p
+         JOIN playlists pl ON p.playlist_id = pl.id
+         JOIN users u ON p.user_id = u.id  -- Join users table
+WHERE p.playlist_id = $1 AND  p.user_id = $2 AND pl.deleted_at IS NULL
+`
+
+type GetUserPlaylistByIdParams struct {
+	PlaylistID string
+	UserID     int64
+}
+
+type GetUserPlaylistByIdRow struct {
+	ID            string
+	Title         string
+	Thumbnail  string
+	Type       PlaylistType
+	ExternalID string
+	TelegramID    int64
+	Tracks        []string
+	AllowedTracks []string
+	Count         pgtype.Int4
+	AllowedCount  pgtype.Int4
+	Time       int32
+	DeletedAt     pgtype.Timestamptz
+	Language      string
+	TopicID       int32
+	Role       PlaylistRole
+}
+
+func (q *Queries) GetUserPlaylistById(ctx context.Context, arg GetUserPlaylistByIdParams) (GetUserPlaylistByIdRow, error) {
+	row := q.db.QueryRow(ctx, getUserPlaylistById, arg.PlaylistID, arg.UserID)
+	var i GetUserPlaylistByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Thumbnail,
+		&i.Type,
+		&i.ExternalID,
+		&i.TelegramID,
+		&i.Tracks,
+		&i.AllowedTracks,
+		&i.Count,
+		&i.AllowedCount,
+		&i.Time,
+		&i.DeletedAt,
+		&i.Language,
+		&i.TopicID,
+		&i.Role,
+	)
+	return i, err
+}
+
+const getUserPlaylists = `-- name: GetUserPlaylists :many
+SELECT
+    pl.id, pl.title, pl.thumbnail, pl.type, pl.external_id, pl.telegram_id, pl.tracks, pl.allowed_tracks, pl.count, pl.allowed_count, pl.time, pl.deleted_at, pl.language, pl.topic_id,
+    p.role
+FROM playlists pl
+         JOIN playlist_permissions p ON pl.id = p.playlist_id
+         JOIN users u ON p.user_id = u.id  -- Join users table
+WHERE p.user_id = $1 AND pl.deleted_at IS NULL
+`
+
+type GetUserPlaylistsRow struct {
+	ID            string
+	Title         string
+	Thumbnail  string
+	Type       PlaylistType
+	ExternalID string
+	TelegramID    int64
+	Tracks        []string
+	AllowedTracks []string
+	Count         pgtype.Int4
+	AllowedCount  pgtype.Int4
+	Time       int32
+	DeletedAt     pgtype.Timestamptz
+	Language      string
+	TopicID       int32
+	Role       PlaylistRole
+}
+
+func (q *Queries) GetUserPlaylists(ctx context.Context, userID int64) ([]GetUserPlaylistsRow, error) {
+	rows, err := q.db.Query(ctx, getUserPlaylists, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserPlaylistsRow
+	for rows.Next() {
+		var i GetUserPlaylistsRow
+		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
 			&i.Thumbnail,
@@ -374,6 +1175,9 @@ func (q *Queries) GetUserPlaylists(ctx context.Context, userID int64) ([]GetUser
 			&i.Count,
 			&i.AllowedCount,
 			&i.Time,
+			&i.DeletedAt,
+			&i.Language,
+			&i.TopicID,
 			&i.Role,
 		); err != nil {
 			return nil, err
@@ -385,3 +1189,363 @@ func (q *Queries) GetUserPlaylists(ctx context.Context, userID int64) ([]GetUser
 	}
 	return items, nil
 }
+
+const getUserSubmissions = `-- name: GetUserSubmissions :many
+SELECT
+    s.track_id, s.created_at,
+    t.title, t.authors, t.thumbnail, t.length, t.explicit
+FROM track_submissions s
+         JOIN tracks t ON s.track_id = t.id
+WHERE s.playlist_id = $1 AND s.user_id = $2
+ORDER BY s.created_at DESC
+LIMIT $3
+`
+
+type GetUserSubmissionsParams struct {
+	PlaylistID string
+	UserID     int64
+	Limit      int32
+}
+
+type GetUserSubmissionsRow struct {
+	TrackID   string
+	CreatedAt pgtype.Timestamptz
+	Title     string
+	Authors   string
+	Thumbnail string
+	Length    int32
+	Explicit  bool
+}
+
+func (q *Queries) GetUserSubmissions(ctx context.Context, arg GetUserSubmissionsParams) ([]GetUserSubmissionsRow, error) {
+	rows, err := q.db.Query(ctx, getUserSubmissions, arg.PlaylistID, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserSubmissionsRow
+	for rows.Next() {
+		var i GetUserSubmissionsRow
+		if err := rows.Scan(
+			&i.TrackID,
+			&i.CreatedAt,
+			&i.Title,
+			&i.Authors,
+			&i.Thumbnail,
+			&i.Length,
+			&i.Explicit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const logAction = `-- name: LogAction :exec
+INSERT INTO moderation_log (playlist_id, actor_id, action, target_user_id, track_id, details)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type LogActionParams struct {
+	PlaylistID   string
+	ActorID      int64
+	Action       string
+	TargetUserID pgtype.Int8
+	TrackID      pgtype.Text
+	Details      string
+}
+
+func (q *Queries) LogAction(ctx context.Context, arg LogActionParams) error {
+	_, err := q.db.Exec(ctx, logAction,
+		arg.PlaylistID,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetUserID,
+		arg.TrackID,
+		arg.Details,
+	)
+	return err
+}
+
+const migratePlaylist = `-- name: MigratePlaylist :one
+UPDATE playlists SET telegram_id = $1
+WHERE telegram_id = $2 AND deleted_at IS NULL
+RETURNING id
+`
+
+type MigratePlaylistParams struct {
+	NewID int64
+	OldID int64
+}
+
+func (q *Queries) MigratePlaylist(ctx context.Context, arg MigratePlaylistParams) (string, error) {
+	row := q.db.QueryRow(ctx, migratePlaylist, arg.NewID, arg.OldID)
+	var id string
+	err := row.Scan(&id)
+	return id, err
+}
+
+const muteUser = `-- name: MuteUser :exec
+INSERT INTO playlist_mutes (playlist_id, user_id, muted_by, reason, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (playlist_id, user_id) DO UPDATE SET
+    muted_by = EXCLUDED.muted_by,
+    reason = EXCLUDED.reason,
+    expires_at = EXCLUDED.expires_at,
+    created_at = now()
+`
+
+type MuteUserParams struct {
+	PlaylistID string
+	UserID     int64
+	MutedBy    int64
+	Reason     string
+	ExpiresAt  pgtype.Timestamptz
+}
+
+func (q *Queries) MuteUser(ctx context.Context, arg MuteUserParams) error {
+	_, err := q.db.Exec(ctx, muteUser,
+		arg.PlaylistID,
+		arg.UserID,
+		arg.MutedBy,
+		arg.Reason,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const revokeApiToken = `-- name: RevokeApiToken :execrows
+UPDATE api_tokens SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+`
+
+type RevokeApiTokenParams struct {
+	ID     string
+	UserID int64
+}
+
+func (q *Queries) RevokeApiToken(ctx context.Context, arg RevokeApiTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeApiToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeSession = `-- name: RevokeSession :exec
+UPDATE sessions SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeSession(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, revokeSession, id)
+	return err
+}
+
+const revokeUserSessions = `-- name: RevokeUserSessions :exec
+UPDATE sessions SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeUserSessions(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, revokeUserSessions, userID)
+	return err
+}
+
+const rotateSession = `-- name: RotateSession :execrows
+UPDATE sessions
+SET
+    previous_hash = refresh_hash,
+    refresh_hash = $1,
+    expires_at = $2
+WHERE id = $3 AND refresh_hash = $4 AND revoked_at IS NULL
+`
+
+type RotateSessionParams struct {
+	NewHash   string
+	ExpiresAt pgtype.Timestamptz
+	ID        string
+	OldHash   string
+}
+
+func (q *Queries) RotateSession(ctx context.Context, arg RotateSessionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rotateSession,
+		arg.NewHash,
+		arg.ExpiresAt,
+		arg.ID,
+		arg.OldHash,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setPlaylistDeleted = `-- name: SetPlaylistDeleted :execrows
+UPDATE playlists
+SET deleted_at = CASE WHEN $1::bool THEN now() END
+WHERE id = $2 AND (deleted_at IS NOT NULL) <> $1::bool
+`
+
+type SetPlaylistDeletedParams struct {
+	Deleted bool
+	ID      string
+}
+
+func (q *Queries) SetPlaylistDeleted(ctx context.Context, arg SetPlaylistDeletedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setPlaylistDeleted, arg.Deleted, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setPlaylistLanguage = `-- name: SetPlaylistLanguage :exec
+UPDATE playlists SET language = $2
+WHERE id = $1
+`
+
+type SetPlaylistLanguageParams struct {
+	ID       string
+	Language string
+}
+
+func (q *Queries) SetPlaylistLanguage(ctx context.Context, arg SetPlaylistLanguageParams) error {
+	_, err := q.db.Exec(ctx, setPlaylistLanguage, arg.ID, arg.Language)
+	return err
+}
+
+const setPlaylistOverride = `-- name: SetPlaylistOverride :exec
+INSERT INTO playlist_overrides (playlist_id, role, capability, allowed)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (playlist_id, role, capability) DO UPDATE SET allowed = EXCLUDED.allowed
+`
+
+type SetPlaylistOverrideParams struct {
+	PlaylistID string
+	Role       PlaylistRole
+	Capability string
+	Allowed    bool
+}
+
+func (q *Queries) SetPlaylistOverride(ctx context.Context, arg SetPlaylistOverrideParams) error {
+	_, err := q.db.Exec(ctx, setPlaylistOverride,
+		arg.PlaylistID,
+		arg.Role,
+		arg.Capability,
+		arg.Allowed,
+	)
+	return err
+}
+
+const touchApiToken = `-- name: TouchApiToken :exec
+UPDATE api_tokens SET last_used_at = now() WHERE id = $1
+`
+
+func (q *Queries) TouchApiToken(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, touchApiToken, id)
+	return err
+}
+
+const unmuteUser = `-- name: UnmuteUser :execrows
+DELETE FROM playlist_mutes WHERE playlist_id = $1 AND user_id = $2
+`
+
+type UnmuteUserParams struct {
+	PlaylistID string
+	UserID     int64
+}
+
+func (q *Queries) UnmuteUser(ctx context.Context, arg UnmuteUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unmuteUser, arg.PlaylistID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertRole = `-- name: UpsertRole :exec
+INSERT INTO playlist_permissions (playlist_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (playlist_id, user_id) DO UPDATE SET role = EXCLUDED.role
+`
+
+type UpsertRoleParams struct {
+	PlaylistID string
+	UserID     int64
+	Role       PlaylistRole
+}
+
+func (q *Queries) UpsertRole(ctx context.Context, arg UpsertRoleParams) error {
+	_, err := q.db.Exec(ctx, upsertRole, arg.PlaylistID, arg.UserID, arg.Role)
+	return err
+}
+
+const upsertTrack = `-- name: UpsertTrack :exec
+INSERT INTO tracks (id, title, authors, thumbnail, length, explicit)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO UPDATE SET
+    title = EXCLUDED.title,
+    authors = EXCLUDED.authors,
+    thumbnail = COALESCE(NULLIF(EXCLUDED.thumbnail, ''), tracks.thumbnail),
+    length = COALESCE(NULLIF(EXCLUDED.length, 0), tracks.length),
+    explicit = tracks.explicit OR EXCLUDED.explicit
+`
+
+type UpsertTrackParams struct {
+	ID        string
+	Title     string
+	Authors   string
+	Thumbnail string
+	Length    int32
+	Explicit  bool
+}
+
+func (q *Queries) UpsertTrack(ctx context.Context, arg UpsertTrackParams) error {
+	_, err := q.db.Exec(ctx, upsertTrack,
+		arg.ID,
+		arg.Title,
+		arg.Authors,
+		arg.Thumbnail,
+		arg.Length,
+		arg.Explicit,
+	)
+	return err
+}
+
+const upsertUser = `-- name: UpsertUser :exec
+INSERT INTO users (id, first_name, last_name, username, language_code, photo_url, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, now())
+ON CONFLICT (id) DO UPDATE SET
+    first_name = EXCLUDED.first_name,
+    last_name = EXCLUDED.last_name,
+    username = EXCLUDED.username,
+    language_code = COALESCE(NULLIF(EXCLUDED.language_code, ''), users.language_code),
+    photo_url = COALESCE(NULLIF(EXCLUDED.photo_url, ''), users.photo_url),
+    updated_at = now()
+`
+
+type UpsertUserParams struct {
+	ID           int64
+	FirstName    string
+	LastName     string
+	Username     string
+	LanguageCode string
+	PhotoUrl     string
+}
+
+func (q *Queries) UpsertUser(ctx context.Context, arg UpsertUserParams) error {
+	_, err := q.db.Exec(ctx, upsertUser,
+		arg.ID,
+		arg.FirstName,
+		arg.LastName,
+		arg.Username,
+		arg.LanguageCode,
+		arg.PhotoUrl,
+	)
+	return err
+}
@@ -0,0 +1,33 @@
+package infra
+
+import (
+	"backend/internal/interfaces"
+	"backend/pkg/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+// storageRoute - откуда бэкенд раздаёт файлы локального хранилища
+const storageRoute = "/api/storage"
+
+func NewStorage(cfg *Config, router *echo.Echo) (interfaces.Storage, error) {
+	if cfg.Storage == "s3" {
+		return storage.NewS3(cfg.S3Endpoint, cfg.S3Bucket, cfg.S3Region, cfg.S3AccessKey, cfg.S3SecretKey, cfg.S3PublicUrl), nil
+	}
+
+	local, err := storage.NewLocal(cfg.StorageDir, cfg.StoragePublicUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	// файлы не перезаписываются под тем же ключом, поэтому кешируются навсегда
+	files := router.Group(storageRoute, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Set("Cache-Control", storage.CacheControl)
+			return next(c)
+		}
+	})
+	files.Static("/", cfg.StorageDir)
+
+	return local, nil
+}
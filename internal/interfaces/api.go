@@ -7,4 +7,15 @@ import (
 
 type SearchAPI interface {
 	Search(ctx context.Context, query string) ([]dto.Track, error)
+	GetTrack(ctx context.Context, id string) (dto.Track, error)
+	GetPlaylist(ctx context.Context, id string) (string, []dto.Track, bool, error)
+}
+
+// Storage - хранилище файлов (обложки плейлистов). Ключ - путь внутри хранилища, например playlists/<id>/<hash>/640.jpg.
+// Под одним ключом всегда одно содержимое, поэтому файлы отдаются с бессрочным кешем
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL - публичная ссылка на файл, URL("") - префикс всех ссылок хранилища
+	URL(key string) string
 }
@@ -5,27 +5,77 @@ import (
 	"backend/internal/transport/api/dto"
 	"backend/internal/transport/bot/models"
 	"context"
+	"time"
 )
 
 type UserService interface {
 	Create(ctx context.Context, id int64) error
 	GetByID(ctx context.Context, id int64) error
+	Get(ctx context.Context, id int64) (dto.User, error)
+	Upsert(ctx context.Context, profile dto.TelegramData) error
 }
 
 type AuthService interface {
-	VerifyToken(authHeader string) (int64, error)
-	GenerateToken(userID int64) (string, error)
-	ParseInitData(initDataRaw string) (int64, error)
+	VerifyToken(ctx context.Context, authHeader string) (int64, string, error)
+	GenerateToken(userID int64, sessionID string) (string, error)
+	ParseInitData(initDataRaw string) (dto.TelegramData, error)
+	ParseLoginWidget(data dto.LoginWidgetData) (dto.TelegramData, error)
+	MintInitData(user dto.TelegramData) (string, error)
+	CreateSession(ctx context.Context, userID int64) (dto.Token, error)
+	Refresh(ctx context.Context, refreshToken string) (dto.Token, error)
+	Revoke(ctx context.Context, sessionID string) error
+	RevokeAll(ctx context.Context, userID int64) error
+	JWKS() dto.JWKS
+}
+
+type TokenService interface {
+	Create(ctx context.Context, name string, scopes []string, playlists []string, expiresIn int, userId int64) (dto.CreatedApiToken, error)
+	List(ctx context.Context, userId int64) ([]dto.ApiToken, error)
+	Revoke(ctx context.Context, tokenId string, userId int64) error
+	Verify(ctx context.Context, token string) (int64, dto.TokenGrant, error)
 }
 
 type PlaylistService interface {
-	Create(ctx context.Context, title string, playlistType queries.PlaylistType, telegramId int64) (dto.Playlist, error)
-	GetByGroup(ctx context.Context, telegramId int64) (dto.Playlist, error)
+	Create(ctx context.Context, title string, playlistType queries.PlaylistType, telegramId int64, topicId int) (dto.Playlist, error)
+	GetByGroup(ctx context.Context, telegramId int64, topicId int) (dto.Playlist, error)
+	ChatPlaylists(ctx context.Context, telegramId int64) ([]string, error)
 	GetById(ctx context.Context, playlistId string, userId int64) (dto.Playlist, error)
 	GetAll(ctx context.Context, userId int64) ([]dto.Playlist, error)
+	Groups(ctx context.Context) (map[string]int64, error)
+	Migrate(ctx context.Context, oldTelegramId, newTelegramId int64) (string, bool, error)
 	Rename(ctx context.Context, playlistId string, title string, userId int64) error
-	UpdatePhoto(ctx context.Context, playlistId string, thumbnail string, userId int64) error
+	UpdatePhoto(ctx context.Context, playlistId string, photo []byte) error
+	UploadCover(ctx context.Context, playlistId string, image []byte, userId int64) (string, error)
+	SetLanguage(ctx context.Context, playlistId string, language string) error
+	GroupLanguage(ctx context.Context, telegramId int64, topicId int) (string, error)
 	Delete(ctx context.Context, playlistId string) error
+	Sequence(ctx context.Context, playlistId string, opts dto.SequenceOptions, userId int64) (dto.Sequence, error)
+	Reorder(ctx context.Context, playlistId string, trackIds []string, userId int64) error
+	Export(ctx context.Context, playlistId string, includePending bool, userId int64) (dto.Playlist, error)
+}
+
+type AccessService interface {
+	Capabilities(ctx context.Context, playlistId string, role queries.PlaylistRole) ([]dto.Capability, error)
+	Can(ctx context.Context, playlistId string, role queries.PlaylistRole, capability dto.Capability) (bool, error)
+	Require(ctx context.Context, playlistId string, role queries.PlaylistRole, capability dto.Capability) error
+	Matrix(ctx context.Context, playlistId string, userId int64) (dto.CapabilityMatrix, error)
+	Holders(ctx context.Context, playlistId string, capability dto.Capability) ([]int64, error)
+	SetOverride(ctx context.Context, playlistId string, role queries.PlaylistRole, capability dto.Capability, allowed *bool, userId int64) error
+}
+
+type AdminService interface {
+	Playlists(ctx context.Context, query string, deleted bool, limit, offset int32) ([]dto.AdminPlaylist, error)
+	Users(ctx context.Context, query string, limit, offset int32) ([]dto.User, error)
+	DeletePlaylist(ctx context.Context, playlistId string, adminId int64) error
+	RestorePlaylist(ctx context.Context, playlistId string, adminId int64) error
+	ReassignOwner(ctx context.Context, playlistId string, targetId int64, adminId int64) error
+	History(ctx context.Context, playlistId string, limit, offset int32) ([]dto.HistoryEntry, error)
+}
+
+type ModerationService interface {
+	Mute(ctx context.Context, playlistId string, targetId int64, duration time.Duration, reason string, userId int64) error
+	Unmute(ctx context.Context, playlistId string, targetId int64, userId int64) error
+	Mutes(ctx context.Context, playlistId string, userId int64) ([]dto.Mute, error)
 }
 
 type PermissionService interface {
@@ -34,6 +84,9 @@ type PermissionService interface {
 	Remove(ctx context.Context, playlist string, userId int64) error
 	Edit(ctx context.Context, role queries.PlaylistRole, playlist string, userId int64) error
 	Get(ctx context.Context, userId int64, role queries.PlaylistRole) (string, error)
+	Transfer(ctx context.Context, playlist string, targetId int64, userId int64) error
+	Leave(ctx context.Context, playlist string, userId int64) error
+	Sync(ctx context.Context, playlist string, users []models.ParticipantData, complete bool) (models.SyncDiff, error)
 }
 
 type TrackService interface {
@@ -43,4 +96,12 @@ type TrackService interface {
 	Decline(ctx context.Context, playlistId string, trackId string, userId int64) error
 	Submit(ctx context.Context, playlistId string, trackId string, userId int64) error
 	Unapprove(ctx context.Context, playlistId string, trackId string, userId int64) error
+	Mine(ctx context.Context, playlistId string, limit int32, userId int64) ([]dto.SubmissionStatus, error)
+	ImportCSV(ctx context.Context, playlistId string, data []byte, mapping dto.ImportMapping, userId int64) (dto.ImportReport, error)
+	ImportPlaylist(ctx context.Context, playlistId string, link string, pending bool, userId int64) (dto.PlaylistImport, error)
+}
+
+type ImageService interface {
+	URL(raw string) string
+	Get(ctx context.Context, hash string, width int) ([]byte, error)
 }
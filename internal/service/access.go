@@ -0,0 +1,237 @@
+package service
+
+import (
+	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
+	"backend/pkg/utils"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// defaultCapabilities - права ролей, если в плейлисте нет переопределений
+var defaultCapabilities = map[queries.PlaylistRole][]dto.Capability{
+	queries.PlaylistRoleViewer: {
+		dto.CapSubmit,
+		dto.CapExport,
+	},
+	queries.PlaylistRoleModerator: {
+		dto.CapSubmit,
+		dto.CapApprove,
+		dto.CapDecline,
+		dto.CapUnapprove,
+		dto.CapReorder,
+		dto.CapExport,
+		dto.CapImport,
+		dto.CapMute,
+	},
+	queries.PlaylistRoleOwner: {
+		dto.CapSubmit,
+		dto.CapApprove,
+		dto.CapDecline,
+		dto.CapUnapprove,
+		dto.CapReorder,
+		dto.CapExport,
+		dto.CapImport,
+		dto.CapMute,
+		dto.CapManageMembers,
+		dto.CapEditSettings,
+	},
+}
+
+// allCapabilities - порядок прав в ответах
+var allCapabilities = defaultCapabilities[queries.PlaylistRoleOwner]
+
+/*
+Access - единая проверка прав в плейлисте: матрица роль -> права и переопределения для конкретного плейлиста.
+
+Права владельца не переопределяются, чтобы он не мог запереть сам себя
+*/
+type Access struct {
+	pool *pgxpool.Pool
+}
+
+func NewAccess(pool *pgxpool.Pool) *Access {
+	return &Access{pool: pool}
+}
+
+// Capabilities - права роли в плейлисте с учётом переопределений
+func (s *Access) Capabilities(ctx context.Context, playlistId string, role queries.PlaylistRole) ([]dto.Capability, error) {
+	rq := queries.New(s.pool)
+
+	overrides, err := rq.GetPlaylistOverrides(ctx, playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyOverrides(role, overrides), nil
+}
+
+// Can - есть ли у роли право в плейлисте
+func (s *Access) Can(ctx context.Context, playlistId string, role queries.PlaylistRole, capability dto.Capability) (bool, error) {
+	capabilities, err := s.Capabilities(ctx, playlistId, role)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(capabilities, capability), nil
+}
+
+// Require - то же, что Can, но возвращает utils.ErrNotEnoughPerms, если права нет
+func (s *Access) Require(ctx context.Context, playlistId string, role queries.PlaylistRole, capability dto.Capability) error {
+	ok, err := s.Can(ctx, playlistId, role, capability)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return utils.ErrNotEnoughPerms
+	}
+
+	return nil
+}
+
+// Matrix - права всех ролей в плейлисте. Доступно любому участнику
+func (s *Access) Matrix(ctx context.Context, playlistId string, userId int64) (dto.CapabilityMatrix, error) {
+	rq := queries.New(s.pool)
+	if _, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	}); err != nil {
+		return dto.CapabilityMatrix{}, err
+	}
+
+	overrides, err := rq.GetPlaylistOverrides(ctx, playlistId)
+	if err != nil {
+		return dto.CapabilityMatrix{}, err
+	}
+
+	result := dto.CapabilityMatrix{
+		Roles:     make(map[queries.PlaylistRole][]dto.Capability, len(defaultCapabilities)),
+		Overrides: make([]dto.CapabilityOverride, len(overrides)),
+	}
+	for role := range defaultCapabilities {
+		result.Roles[role] = applyOverrides(role, overrides)
+	}
+	for i, override := range overrides {
+		result.Overrides[i] = dto.CapabilityOverride{
+			Role:       override.Role,
+			Capability: dto.Capability(override.Capability),
+			Allowed:    override.Allowed,
+		}
+	}
+
+	return result, nil
+}
+
+// Holders - участники плейлиста, у которых есть право capability с учётом переопределений
+func (s *Access) Holders(ctx context.Context, playlistId string, capability dto.Capability) ([]int64, error) {
+	rq := queries.New(s.pool)
+
+	members, err := rq.GetPlaylistMembers(ctx, playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := rq.GetPlaylistOverrides(ctx, playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []int64
+	for _, member := range members {
+		if slices.Contains(applyOverrides(member.Role, overrides), capability) {
+			result = append(result, member.ID)
+		}
+	}
+
+	return result, nil
+}
+
+// SetOverride - разрешить или запретить право роли в плейлисте. allowed == nil - вернуть значение по умолчанию
+func (s *Access) SetOverride(ctx context.Context, playlistId string, role queries.PlaylistRole, capability dto.Capability, allowed *bool, userId int64) error {
+	if role == queries.PlaylistRoleOwner {
+		return fmt.Errorf("%w: owner capabilities can not be changed", utils.ErrInvalidInput)
+	}
+
+	if !slices.Contains(allCapabilities, capability) {
+		return fmt.Errorf("%w: unknown capability %s", utils.ErrInvalidInput, capability)
+	}
+
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.Require(ctx, playlistId, playlist.Role, dto.CapEditSettings); err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("%s %s=default", role, capability)
+	if allowed != nil {
+		details = fmt.Sprintf("%s %s=%t", role, capability, *allowed)
+	}
+
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		var err error
+		// совпадающее со значением по умолчанию переопределение не хранится
+		if allowed == nil || *allowed == slices.Contains(defaultCapabilities[role], capability) {
+			err = tq.DeletePlaylistOverride(ctx, queries.DeletePlaylistOverrideParams{
+				PlaylistID: playlistId,
+				Role:       role,
+				Capability: string(capability),
+			})
+		} else {
+			err = tq.SetPlaylistOverride(ctx, queries.SetPlaylistOverrideParams{
+				PlaylistID: playlistId,
+				Role:       role,
+				Capability: string(capability),
+				Allowed:    *allowed,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		return tq.LogAction(ctx, queries.LogActionParams{
+			PlaylistID: playlistId,
+			ActorID:    userId,
+			Action:     dto.ActionOverride,
+			Details:    details,
+		})
+	})
+}
+
+func applyOverrides(role queries.PlaylistRole, overrides []queries.PlaylistOverride) []dto.Capability {
+	capabilities := slices.Clone(defaultCapabilities[role])
+	if role == queries.PlaylistRoleOwner {
+		return capabilities
+	}
+
+	for _, override := range overrides {
+		if override.Role != role {
+			continue
+		}
+
+		capability := dto.Capability(override.Capability)
+		if override.Allowed && !slices.Contains(capabilities, capability) {
+			capabilities = append(capabilities, capability)
+		}
+		if !override.Allowed {
+			capabilities = slices.DeleteFunc(capabilities, func(c dto.Capability) bool {
+				return c == capability
+			})
+		}
+	}
+
+	// порядок как в allCapabilities, независимо от порядка переопределений
+	return slices.DeleteFunc(slices.Clone(allCapabilities), func(c dto.Capability) bool {
+		return !slices.Contains(capabilities, c)
+	})
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+Admin - действия глобальных администраторов. Проверка, что юзер администратор, делается в middleware,
+поэтому роли в плейлистах здесь не проверяются. Все изменения пишутся в историю модерации
+*/
+type Admin struct {
+	pool *pgxpool.Pool
+}
+
+func NewAdmin(pool *pgxpool.Pool) *Admin {
+	return &Admin{pool: pool}
+}
+
+// Playlists - поиск плейлистов по названию, ID или ID чата
+func (s *Admin) Playlists(ctx context.Context, query string, deleted bool, limit, offset int32) ([]dto.AdminPlaylist, error) {
+	rq := queries.New(s.pool)
+
+	playlists, err := rq.AdminSearchPlaylists(ctx, queries.AdminSearchPlaylistsParams{
+		Query:   strings.TrimSpace(query),
+		Deleted: deleted,
+		Lim:     limit,
+		Off:     offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.AdminPlaylist, len(playlists))
+	for i, playlist := range playlists {
+		result[i] = dto.AdminPlaylist{
+			Id:           playlist.ID,
+			Title:        playlist.Title,
+			Thumbnail:    playlist.Thumbnail,
+			Type:         string(playlist.Type),
+			TelegramId:   playlist.TelegramID.Int64,
+			TopicId:      int(playlist.TopicID),
+			Count:        int(playlist.Count.Int32),
+			AllowedCount: int(playlist.AllowedCount.Int32),
+			OwnerId:      playlist.OwnerID,
+			DeletedAt:    timePtr(playlist.DeletedAt),
+		}
+	}
+
+	return result, nil
+}
+
+// Users - поиск юзеров по имени, username или ID
+func (s *Admin) Users(ctx context.Context, query string, limit, offset int32) ([]dto.User, error) {
+	rq := queries.New(s.pool)
+
+	users, err := rq.AdminSearchUsers(ctx, queries.AdminSearchUsersParams{
+		Query: strings.TrimPrefix(strings.TrimSpace(query), "@"),
+		Lim:   limit,
+		Off:   offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.User, len(users))
+	for i, user := range users {
+		result[i] = userFromRow(user)
+	}
+
+	return result, nil
+}
+
+// DeletePlaylist - скрыть плейлист у всех участников. Данные остаются, плейлист можно восстановить
+func (s *Admin) DeletePlaylist(ctx context.Context, playlistId string, adminId int64) error {
+	return s.setDeleted(ctx, playlistId, true, adminId)
+}
+
+// RestorePlaylist - вернуть удалённый плейлист. У группы (темы) может быть только один живой плейлист
+func (s *Admin) RestorePlaylist(ctx context.Context, playlistId string, adminId int64) error {
+	return s.setDeleted(ctx, playlistId, false, adminId)
+}
+
+func (s *Admin) setDeleted(ctx context.Context, playlistId string, deleted bool, adminId int64) error {
+	action := dto.ActionRestore
+	if deleted {
+		action = dto.ActionDelete
+	}
+
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		if !deleted {
+			conflictId, err := tq.GetGroupPlaylistConflict(ctx, playlistId)
+			if err == nil {
+				return fmt.Errorf("%w: group already has playlist %s", utils.ErrInvalidInput, conflictId)
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+		}
+
+		changed, err := tq.SetPlaylistDeleted(ctx, queries.SetPlaylistDeletedParams{
+			Deleted: deleted,
+			ID:      playlistId,
+		})
+		if err != nil {
+			return err
+		}
+
+		// плейлиста нет или он уже в нужном состоянии
+		if changed == 0 {
+			return pgx.ErrNoRows
+		}
+
+		return tq.LogAction(ctx, queries.LogActionParams{
+			PlaylistID: playlistId,
+			ActorID:    adminId,
+			Action:     action,
+		})
+	})
+}
+
+// ReassignOwner - назначить владельца плейлиста. Юзер должен быть известен сервису, участником он станет автоматически
+func (s *Admin) ReassignOwner(ctx context.Context, playlistId string, targetId int64, adminId int64) error {
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		if _, err := tq.GetPlaylistById(ctx, playlistId); err != nil {
+			return err
+		}
+
+		if _, err := tq.GetUserById(ctx, targetId); err != nil {
+			return err
+		}
+
+		if err := demoteOwner(ctx, tq, queries.PlaylistRoleOwner, playlistId, targetId); err != nil {
+			return err
+		}
+
+		if err := tq.UpsertRole(ctx, queries.UpsertRoleParams{
+			PlaylistID: playlistId,
+			UserID:     targetId,
+			Role:       queries.PlaylistRoleOwner,
+		}); err != nil {
+			return err
+		}
+
+		return tq.LogAction(ctx, queries.LogActionParams{
+			PlaylistID:   playlistId,
+			ActorID:      adminId,
+			Action:       dto.ActionReassign,
+			TargetUserID: pgtype.Int8{Int64: targetId, Valid: true},
+		})
+	})
+}
+
+// History - история модерации плейлиста, новые записи первыми
+func (s *Admin) History(ctx context.Context, playlistId string, limit, offset int32) ([]dto.HistoryEntry, error) {
+	rq := queries.New(s.pool)
+
+	entries, err := rq.GetPlaylistHistory(ctx, queries.GetPlaylistHistoryParams{
+		PlaylistID: playlistId,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.HistoryEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = dto.HistoryEntry{
+			Id:           entry.ID,
+			ActorId:      entry.ActorID,
+			Action:       entry.Action,
+			TargetUserId: entry.TargetUserID.Int64,
+			TrackId:      entry.TrackID.String,
+			Details:      entry.Details,
+			CreatedAt:    entry.CreatedAt.Time,
+		}
+	}
+
+	return result, nil
+}
@@ -2,105 +2,402 @@ package service
 
 import (
 	"backend/internal/infra"
+	"backend/internal/infra/queries"
 	"backend/internal/transport/api/dto"
 	"backend/pkg/utils"
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
 	initdata "github.com/telegram-mini-apps/init-data-golang"
 )
 
 type Auth struct {
-	secret   string
+	pool *pgxpool.Pool
+	keys *infra.KeySet
+
 	botToken string
+	issuer   string
+	audience string
 
-	expires time.Duration
+	expires        time.Duration
+	refreshExpires time.Duration
+	initDataTTL    time.Duration
 }
 
 // NewAuth - создать новый экземпляр сервиса авторизации
-func NewAuth(cfg *infra.Config) *Auth {
+func NewAuth(cfg *infra.Config, pool *pgxpool.Pool, keys *infra.KeySet) *Auth {
 	return &Auth{
-		secret:   cfg.JwtSecret,
-		botToken: cfg.BotToken,
-		expires:  time.Hour,
+		pool:           pool,
+		keys:           keys,
+		botToken:       cfg.BotToken,
+		issuer:         cfg.JwtIssuer,
+		audience:       cfg.JwtAudience,
+		expires:        cfg.AccessTokenTTL,
+		refreshExpires: cfg.RefreshTokenTTL,
+		initDataTTL:    time.Hour,
 	}
 }
 
-// VerifyToken - проверить токен на подлинность
-func (s *Auth) VerifyToken(authHeader string) (int64, error) {
+// VerifyToken - проверить токен на подлинность и что его сессия не отозвана. Возвращает ID юзера и ID сессии
+func (s *Auth) VerifyToken(ctx context.Context, authHeader string) (int64, string, error) {
 	tokenStr := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
 	if tokenStr == "" {
-		return 0, utils.ErrInvalidToken
+		return 0, "", utils.ErrInvalidToken
 	}
 
-	token, err := jwt.Parse(tokenStr, func(_ *jwt.Token) (interface{}, error) {
-		return []byte(s.secret), nil
-	})
+	// алгоритм проверяется до проверки подписи: он должен совпадать с алгоритмом ключа из kid
+	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := s.keys.Get(kid)
+		if !ok || t.Method.Alg() != key.Method.Alg() {
+			return nil, utils.ErrInvalidToken
+		}
+
+		return key.Verify, nil
+	},
+		jwt.WithValidMethods([]string{
+			jwt.SigningMethodHS256.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+			jwt.SigningMethodRS256.Alg(),
+		}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
-	if !token.Valid || token.Method != jwt.SigningMethodHS256 {
-		return 0, utils.ErrInvalidToken
+	if !token.Valid {
+		return 0, "", utils.ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, utils.ErrInvalidToken
+		return 0, "", utils.ErrInvalidToken
 	}
 
 	userIDstr, ok := claims["sub"].(string)
 	if !ok {
-		return 0, utils.ErrInvalidToken
+		return 0, "", utils.ErrInvalidToken
 	}
 
 	userID, err := strconv.ParseInt(userIDstr, 10, 64)
 	if err != nil {
-		return 0, utils.ErrInvalidToken
+		return 0, "", utils.ErrInvalidToken
+	}
+
+	sessionID, ok := claims["sid"].(string)
+	if !ok {
+		return 0, "", utils.ErrInvalidToken
+	}
+
+	rq := queries.New(s.pool)
+	session, err := rq.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, "", utils.ErrInvalidToken
+		}
+		return 0, "", err
+	}
+
+	if session.UserID != userID || session.RevokedAt.Valid {
+		return 0, "", utils.ErrInvalidToken
 	}
 
-	return userID, nil
+	return userID, sessionID, nil
 }
 
-// GenerateToken - создать новый JWT токен
-func (s *Auth) GenerateToken(userID int64) (string, error) {
+// GenerateToken - создать новый JWT токен для сессии
+func (s *Auth) GenerateToken(userID int64, sessionID string) (string, error) {
+	key := s.keys.Active()
+
 	claims := jwt.MapClaims{
 		"sub": strconv.FormatInt(userID, 10),
+		"sid": sessionID,
+		"iss": s.issuer,
+		"aud": s.audience,
 		"iat": time.Now().Unix(),
 		"exp": time.Now().Add(s.expires).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
 
-	return token.SignedString([]byte(s.secret))
+	return token.SignedString(key.Sign)
 }
 
-// ParseInitData - извлечь Telegram ID из Init Data Raw
-func (s *Auth) ParseInitData(initDataRaw string) (int64, error) {
-	if err := initdata.Validate(initDataRaw, s.botToken, s.expires); err != nil {
-		return 0, utils.ErrInvalidInitData
+// JWKS - публичные ключи для проверки токенов другими сервисами. HS256 секреты не публикуются
+func (s *Auth) JWKS() dto.JWKS {
+	result := dto.JWKS{Keys: make([]dto.JWK, 0)}
+
+	for _, key := range s.keys.Public() {
+		jwk := dto.JWK{
+			Kid: key.ID,
+			Alg: key.Method.Alg(),
+			Use: "sig",
+		}
+
+		switch public := key.Public.(type) {
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		default:
+			continue
+		}
+
+		result.Keys = append(result.Keys, jwk)
+	}
+
+	return result
+}
+
+// ParseInitData - извлечь профиль Telegram юзера из Init Data Raw
+func (s *Auth) ParseInitData(initDataRaw string) (dto.TelegramData, error) {
+	if err := initdata.Validate(initDataRaw, s.botToken, s.initDataTTL); err != nil {
+		return dto.TelegramData{}, utils.ErrInvalidInitData
 	}
 
 	initDataValues, err := url.ParseQuery(initDataRaw)
 	if err != nil {
-		return 0, utils.ErrInvalidInitData
+		return dto.TelegramData{}, utils.ErrInvalidInitData
 	}
 
 	initDataUser := initDataValues.Get("user")
 
 	if initDataUser == "" {
-		return 0, utils.ErrInvalidInitData
+		return dto.TelegramData{}, utils.ErrInvalidInitData
 	}
 
 	user := dto.TelegramData{}
 	err = sonic.Unmarshal([]byte(initDataUser), &user)
+	if err != nil || user.ID == 0 {
+		return dto.TelegramData{}, utils.ErrInvalidInitData
+	}
+
+	return user, nil
+}
+
+// MintInitData - подписать init data токеном бота, как это делает Telegram. Только для разработки
+func (s *Auth) MintInitData(user dto.TelegramData) (string, error) {
+	userJson, err := sonic.MarshalString(user)
+	if err != nil {
+		return "", err
+	}
+
+	authDate := time.Now()
+	payload := map[string]string{
+		"user":      userJson,
+		"chat_type": "private",
+	}
+
+	values := url.Values{}
+	for key, value := range payload {
+		values.Set(key, value)
+	}
+	values.Set("auth_date", strconv.FormatInt(authDate.Unix(), 10))
+	values.Set("hash", initdata.Sign(payload, s.botToken, authDate))
+
+	return values.Encode(), nil
+}
+
+/*
+ParseLoginWidget - проверить данные Telegram Login Widget и извлечь профиль юзера.
+
+Подпись - HMAC-SHA256 от отсортированных полей "key=value", разделённых переводом строки, ключ - SHA256 от токена бота
+*/
+func (s *Auth) ParseLoginWidget(data dto.LoginWidgetData) (dto.TelegramData, error) {
+	fields := map[string]string{
+		"id":        strconv.FormatInt(data.ID, 10),
+		"auth_date": strconv.FormatInt(data.AuthDate, 10),
+	}
+	for key, value := range map[string]string{
+		"first_name": data.FirstName,
+		"last_name":  data.LastName,
+		"username":   data.Username,
+		"photo_url":  data.PhotoUrl,
+	} {
+		// виджет не присылает пустые поля, поэтому они не участвуют в подписи
+		if value != "" {
+			fields[key] = value
+		}
+	}
+
+	pairs := make([]string, 0, len(fields))
+	for key, value := range fields {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+
+	secret := sha256.Sum256([]byte(s.botToken))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(strings.Join(pairs, "\n")))
+
+	expected, err := hex.DecodeString(data.Hash)
+	if err != nil || !hmac.Equal(mac.Sum(nil), expected) {
+		return dto.TelegramData{}, utils.ErrInvalidLogin
+	}
+
+	authDate := time.Unix(data.AuthDate, 0)
+	if time.Since(authDate) > s.initDataTTL || time.Until(authDate) > time.Minute {
+		return dto.TelegramData{}, utils.ErrInvalidLogin
+	}
+
+	return dto.TelegramData{
+		ID:        data.ID,
+		FirstName: data.FirstName,
+		LastName:  data.LastName,
+		Username:  data.Username,
+		PhotoUrl:  data.PhotoUrl,
+	}, nil
+}
+
+// CreateSession - начать новую сессию: выдать JWT и refresh токен
+func (s *Auth) CreateSession(ctx context.Context, userID int64) (dto.Token, error) {
+	sessionID := ulid.Make().String()
+
+	refresh, hash, err := newRefreshToken()
+	if err != nil {
+		return dto.Token{}, err
+	}
+
+	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		if err := tq.DeleteExpiredSessions(ctx, userID); err != nil {
+			return err
+		}
+
+		return tq.CreateSession(ctx, queries.CreateSessionParams{
+			ID:          sessionID,
+			UserID:      userID,
+			RefreshHash: hash,
+			ExpiresAt:   pgtype.Timestamptz{Time: time.Now().Add(s.refreshExpires), Valid: true},
+		})
+	}); err != nil {
+		return dto.Token{}, err
+	}
+
+	return s.tokenPair(userID, sessionID, refresh)
+}
+
+/*
+Refresh - обменять refresh токен на новую пару токенов. Старый refresh токен перестаёт действовать.
+
+Если предъявлен уже использованный refresh токен - токен, скорее всего, украден, сессия отзывается
+*/
+func (s *Auth) Refresh(ctx context.Context, refreshToken string) (dto.Token, error) {
+	oldHash := hashToken(refreshToken)
+
+	rq := queries.New(s.pool)
+	session, err := rq.GetSessionByRefresh(ctx, oldHash)
 	if err != nil {
-		return 0, utils.ErrInvalidInitData
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dto.Token{}, utils.ErrInvalidToken
+		}
+		return dto.Token{}, err
+	}
+
+	if session.RevokedAt.Valid || session.ExpiresAt.Time.Before(time.Now()) {
+		return dto.Token{}, utils.ErrInvalidToken
 	}
 
-	return user.ID, nil
+	if session.RefreshHash != oldHash {
+		if err := s.Revoke(ctx, session.ID); err != nil {
+			return dto.Token{}, err
+		}
+
+		return dto.Token{}, utils.ErrInvalidToken
+	}
+
+	refresh, newHash, err := newRefreshToken()
+	if err != nil {
+		return dto.Token{}, err
+	}
+
+	var rotated int64
+	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		rotated, err = tq.RotateSession(ctx, queries.RotateSessionParams{
+			NewHash:   newHash,
+			ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(s.refreshExpires), Valid: true},
+			ID:        session.ID,
+			OldHash:   oldHash,
+		})
+		return err
+	}); err != nil {
+		return dto.Token{}, err
+	}
+
+	// параллельный запрос успел обновить сессию раньше
+	if rotated == 0 {
+		return dto.Token{}, utils.ErrInvalidToken
+	}
+
+	return s.tokenPair(session.UserID, session.ID, refresh)
+}
+
+// Revoke - отозвать сессию
+func (s *Auth) Revoke(ctx context.Context, sessionID string) error {
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		return tq.RevokeSession(ctx, sessionID)
+	})
+}
+
+// RevokeAll - отозвать все сессии юзера
+func (s *Auth) RevokeAll(ctx context.Context, userID int64) error {
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		return tq.RevokeUserSessions(ctx, userID)
+	})
+}
+
+func (s *Auth) tokenPair(userID int64, sessionID, refresh string) (dto.Token, error) {
+	token, err := s.GenerateToken(userID, sessionID)
+	if err != nil {
+		return dto.Token{}, err
+	}
+
+	return dto.Token{
+		Token:        token,
+		RefreshToken: refresh,
+		ExpiresIn:    int(s.expires.Seconds()),
+	}, nil
+}
+
+// newRefreshToken - случайный refresh токен и его хэш для хранения в базе
+func newRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
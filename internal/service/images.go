@@ -0,0 +1,177 @@
+package service
+
+import (
+	"backend/internal/infra"
+	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
+	"backend/pkg/images"
+	"backend/pkg/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/sync/singleflight"
+)
+
+// imageMaxBytes - картинки больше не скачиваются
+const imageMaxBytes = 5 << 20
+
+// imageFetchTimeout - сколько ждать скачивания и обработки одной картинки
+const imageFetchTimeout = 15 * time.Second
+
+/*
+Images - прокси для обложек треков: мини апп грузит их с нашего сервера, а не напрямую с серверов Google.
+Ссылка - sha256 исходной ссылки, исходная ссылка ищется среди обложек треков в базе, поэтому через прокси
+нельзя скачать произвольную картинку. Каждая картинка скачивается один раз, оригинал и уменьшенные копии лежат в кеше на диске
+*/
+type Images struct {
+	pool     *pgxpool.Pool
+	cache    *images.Cache
+	client   *http.Client
+	proxyUrl string
+	hosts    []string
+	group    singleflight.Group
+}
+
+func NewImages(cfg *infra.Config, pool *pgxpool.Pool) (*Images, error) {
+	cache, err := images.NewCache(cfg.ImageCacheDir, cfg.ImageCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Images{
+		pool:     pool,
+		cache:    cache,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		proxyUrl: strings.TrimSuffix(cfg.ImageProxyUrl, "/"),
+		hosts:    cfg.ImageHosts,
+	}, nil
+}
+
+/*
+URL - ссылка на картинку через прокси. Пустые ссылки и ссылки на чужие хосты (например, наше хранилище) не меняются,
+без IMAGE_PROXY_URL прокси выключен и все ссылки остаются исходными
+*/
+func (s *Images) URL(raw string) string {
+	if raw == "" || s.proxyUrl == "" || !s.allowed(raw) {
+		return raw
+	}
+
+	sum := sha256.Sum256([]byte(raw))
+	return s.proxyUrl + "/" + hex.EncodeToString(sum[:])
+}
+
+// Get - картинка в JPEG по хешу ссылки, уменьшенная до width (0 - исходный размер)
+func (s *Images) Get(ctx context.Context, hash string, width int) ([]byte, error) {
+	if !slices.Contains(dto.ImageWidths, width) {
+		return nil, fmt.Errorf("%w: unsupported width %d", utils.ErrInvalidInput, width)
+	}
+
+	key := hash + "-" + strconv.Itoa(width)
+	if data, ok := s.cache.Get(key); ok {
+		return data, nil
+	}
+
+	// одновременные запросы одной картинки ждут одно скачивание. Оно идёт в своём контексте:
+	// если первый запрос отменят, остальные всё равно получат картинку
+	result, err, _ := s.group.Do(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), imageFetchTimeout)
+		defer cancel()
+
+		original, err := s.original(ctx, hash)
+		if err != nil || width == 0 {
+			return original, err
+		}
+
+		img, err := images.Decode(original)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := images.EncodeJPEG(images.Resize(img, width))
+		if err != nil {
+			return nil, err
+		}
+
+		return data, s.cache.Put(key, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]byte), nil
+}
+
+// original - картинка в исходном размере: из кеша или скачать по ссылке из базы и перекодировать в JPEG
+func (s *Images) original(ctx context.Context, hash string) ([]byte, error) {
+	key := hash + "-0"
+	if data, ok := s.cache.Get(key); ok {
+		return data, nil
+	}
+
+	raw, err := queries.New(s.pool).GetThumbnailByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.allowed(raw) {
+		return nil, fmt.Errorf("%w: image host is not allowed", utils.ErrInvalidInput)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, raw, nil)
+	if err != nil {
+		return nil, err
+	}
+	// без webp в Accept Google отдаёт JPEG, который умеет декодировать стандартная библиотека
+	req.Header.Set("Accept", "image/jpeg,image/png,image/gif")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("image proxy: " + raw + ": status " + strconv.Itoa(resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, imageMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > imageMaxBytes {
+		return nil, errors.New("image proxy: " + raw + ": image is too large")
+	}
+
+	img, err := images.Decode(body)
+	if err != nil {
+		return nil, errors.New("image proxy: " + raw + ": " + err.Error())
+	}
+
+	data, err := images.EncodeJPEG(img)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, s.cache.Put(key, data)
+}
+
+// allowed - https ссылка на хост из списка IMAGE_HOSTS
+func (s *Images) allowed(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "https" && slices.Contains(s.hosts, u.Hostname())
+}
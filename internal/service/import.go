@@ -0,0 +1,344 @@
+package service
+
+import (
+	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
+	"backend/pkg/utils"
+	"backend/pkg/youtube"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxImportRows - сколько строк можно импортировать за раз, каждая строка без ссылки - это запрос к поиску
+const maxImportRows = 300
+
+/*
+ImportCSV - импортировать треки из CSV (например, ответы из Google Forms). Треки добавляются на модерацию.
+
+Строка со ссылкой ищется по ID из ссылки, без ссылки - через поиск по названию и исполнителю.
+Если найденный трек не похож на запрошенный, строка помечается как ambiguous и не добавляется.
+Предложившим новые треки записывается импортировавший, а имя из колонки submitter сохраняется рядом
+*/
+func (s *Track) ImportCSV(ctx context.Context, playlistId string, data []byte, mapping dto.ImportMapping, userId int64) (dto.ImportReport, error) {
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return dto.ImportReport{}, err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapImport); err != nil {
+		return dto.ImportReport{}, err
+	}
+
+	rows, err := readImportRows(data, mapping)
+	if err != nil {
+		return dto.ImportReport{}, err
+	}
+
+	report := dto.ImportReport{Rows: rows}
+	tracks := playlist.Tracks
+	submitters := make(map[string]string) // новый трек -> имя из колонки submitter
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		s.resolveRow(ctx, row)
+
+		switch row.Status {
+		case dto.ImportMatched:
+			report.Matched++
+			if !slices.Contains(tracks, row.Track.Id) {
+				tracks = append(tracks, row.Track.Id)
+				submitters[row.Track.Id] = row.Submitter
+			}
+		case dto.ImportAmbiguous:
+			report.Ambiguous++
+		case dto.ImportFailed:
+			report.Failed++
+		}
+	}
+
+	if len(tracks) == len(playlist.Tracks) {
+		return report, nil
+	}
+
+	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		if err := tq.EditPlaylist(ctx, queries.EditPlaylistParams{
+			ID:     playlistId,
+			Tracks: tracks,
+		}); err != nil {
+			return err
+		}
+
+		for trackId, submitter := range submitters {
+			if err := tq.AddSubmission(ctx, queries.AddSubmissionParams{
+				PlaylistID:    playlistId,
+				TrackID:       trackId,
+				UserID:        userId,
+				SubmitterName: submitter,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return dto.ImportReport{}, err
+	}
+
+	return report, nil
+}
+
+/*
+ImportPlaylist - импортировать плейлист Youtube Music по ссылке.
+
+Треки сохраняются в базу и добавляются в разрешённые (или на модерацию, если pending). ID исходного плейлиста сохраняется в external_id
+*/
+func (s *Track) ImportPlaylist(ctx context.Context, playlistId, link string, pending bool, userId int64) (dto.PlaylistImport, error) {
+	externalId, ok := youtube.ParsePlaylistID(link)
+	if !ok {
+		return dto.PlaylistImport{}, fmt.Errorf("%w: unsupported playlist link", utils.ErrInvalidInput)
+	}
+
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return dto.PlaylistImport{}, err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapImport); err != nil {
+		return dto.PlaylistImport{}, err
+	}
+
+	// импорт сразу в разрешённые - это одобрение треков
+	if !pending {
+		if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapApprove); err != nil {
+			return dto.PlaylistImport{}, err
+		}
+	}
+
+	title, found, truncated, err := s.youtube.GetPlaylist(ctx, externalId)
+	if err != nil {
+		return dto.PlaylistImport{}, err
+	}
+
+	result := dto.PlaylistImport{
+		ExternalId: externalId,
+		Title:      title,
+		Found:      len(found),
+		Truncated:  truncated,
+	}
+
+	tracks := playlist.Tracks
+	allowedTracks := playlist.AllowedTracks
+	for _, track := range found {
+		if !slices.Contains(tracks, track.Id) {
+			tracks = append(tracks, track.Id)
+			result.Added++
+		}
+
+		if !pending && !slices.Contains(allowedTracks, track.Id) {
+			allowedTracks = append(allowedTracks, track.Id)
+		}
+	}
+
+	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		// в ответе browse может не быть длительности и отметки explicit: UpsertTrack не затирает уже известные значения
+		for _, track := range found {
+			if err := tq.UpsertTrack(ctx, queries.UpsertTrackParams{
+				ID:        track.Id,
+				Title:     track.Title,
+				Authors:   track.Authors,
+				Thumbnail: track.Thumbnail,
+				Length:    track.Length,
+				Explicit:  track.Explicit,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return tq.EditPlaylist(ctx, queries.EditPlaylistParams{
+			ID:            playlistId,
+			Tracks:        tracks,
+			AllowedTracks: allowedTracks,
+			ExternalID:    pgtype.Text{String: externalId, Valid: true},
+		})
+	}); err != nil {
+		return dto.PlaylistImport{}, err
+	}
+
+	return result, nil
+}
+
+// resolveRow - найти трек для строки импорта и проставить статус
+func (s *Track) resolveRow(ctx context.Context, row *dto.ImportRow) {
+	if row.Link != "" {
+		id, ok := youtube.ParseTrackID(row.Link)
+		if !ok {
+			row.Status = dto.ImportFailed
+			row.Error = "unsupported link"
+			return
+		}
+
+		track, err := s.getOrFetch(ctx, id)
+		if err != nil {
+			row.Status = dto.ImportFailed
+			row.Error = err.Error()
+			return
+		}
+
+		row.Status = dto.ImportMatched
+		row.Track = &track
+		return
+	}
+
+	if row.Title == "" {
+		row.Status = dto.ImportFailed
+		row.Error = "no title and no link"
+		return
+	}
+
+	results, err := s.Search(ctx, strings.TrimSpace(row.Artist+" "+row.Title))
+	// ошибка поиска (например, лимит запросов) - не то же самое, что пустой результат: строку можно повторить
+	if err != nil {
+		row.Status = dto.ImportFailed
+		row.Error = err.Error()
+		return
+	}
+	if len(results) == 0 {
+		row.Status = dto.ImportFailed
+		row.Error = "nothing found"
+		return
+	}
+
+	if similar(results[0].Title, row.Title) && (row.Artist == "" || similar(results[0].Authors, row.Artist)) {
+		row.Status = dto.ImportMatched
+		row.Track = &results[0]
+		return
+	}
+
+	row.Status = dto.ImportAmbiguous
+	row.Candidates = results[:min(3, len(results))]
+}
+
+// getOrFetch - получить трек из базы, если его нет - загрузить с площадки и сохранить
+func (s *Track) getOrFetch(ctx context.Context, id string) (dto.Track, error) {
+	track, err := s.GetById(ctx, id)
+	if err == nil {
+		return track, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return dto.Track{}, err
+	}
+
+	track, err = s.youtube.GetTrack(ctx, id)
+	if err != nil {
+		return dto.Track{}, err
+	}
+
+	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		return tq.CreateTrack(ctx, queries.CreateTrackParams{
+			ID:        track.Id,
+			Title:     track.Title,
+			Authors:   track.Authors,
+			Thumbnail: track.Thumbnail,
+			Length:    track.Length,
+			Explicit:  track.Explicit,
+		})
+	}); err != nil {
+		return dto.Track{}, err
+	}
+
+	track.Thumbnail = s.images.URL(track.Thumbnail)
+
+	return track, nil
+}
+
+// readImportRows - разобрать CSV по названиям колонок из mapping
+func readImportRows(data []byte, mapping dto.ImportMapping) ([]dto.ImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read csv header", utils.ErrInvalidInput)
+	}
+
+	column := func(name string) int {
+		return slices.IndexFunc(header, func(h string) bool {
+			return name != "" && strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name))
+		})
+	}
+
+	titleCol, artistCol, linkCol, submitterCol := column(mapping.Title), column(mapping.Artist), column(mapping.Link), column(mapping.Submitter)
+	if titleCol == -1 && linkCol == -1 {
+		return nil, fmt.Errorf("%w: neither title column %q nor link column %q found", utils.ErrInvalidInput, mapping.Title, mapping.Link)
+	}
+
+	cell := func(record []string, col int) string {
+		if col == -1 || col >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[col])
+	}
+
+	var rows []dto.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", utils.ErrInvalidInput, err.Error())
+		}
+
+		if !slices.ContainsFunc(record, func(v string) bool { return strings.TrimSpace(v) != "" }) {
+			continue
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", utils.ErrInvalidInput, maxImportRows)
+		}
+
+		rows = append(rows, dto.ImportRow{
+			Row:       len(rows) + 1,
+			Title:     cell(record, titleCol),
+			Artist:    cell(record, artistCol),
+			Link:      cell(record, linkCol),
+			Submitter: cell(record, submitterCol),
+		})
+	}
+
+	return rows, nil
+}
+
+// similar - похожи ли строки без учёта регистра, пунктуации и лишних слов вокруг
+func similar(a, b string) bool {
+	a, b = normalize(a), normalize(b)
+	if a == "" || b == "" {
+		return false
+	}
+
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
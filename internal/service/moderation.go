@@ -0,0 +1,230 @@
+package service
+
+import (
+	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// roleRank - кого можно заглушить: только участников с ролью ниже своей
+var roleRank = map[queries.PlaylistRole]int{
+	queries.PlaylistRoleViewer:    0,
+	queries.PlaylistRoleModerator: 1,
+	queries.PlaylistRoleOwner:     2,
+}
+
+/*
+Moderation - список заглушённых участников плейлиста. Заглушённый остаётся участником (и в группе Telegram),
+но не может предлагать треки. Список хранится отдельно от ролей, поэтому обновления участников из Telegram его не сбрасывают
+*/
+type Moderation struct {
+	pool   *pgxpool.Pool
+	access *Access
+}
+
+func NewModeration(pool *pgxpool.Pool, access *Access) *Moderation {
+	return &Moderation{pool: pool, access: access}
+}
+
+// Mute - запретить участнику предлагать треки. duration == 0 - до снятия вручную
+func (s *Moderation) Mute(ctx context.Context, playlistId string, targetId int64, duration time.Duration, reason string, userId int64) error {
+	if targetId == userId {
+		return fmt.Errorf("%w: can not mute yourself", utils.ErrInvalidInput)
+	}
+
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapMute); err != nil {
+		return err
+	}
+
+	target, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     targetId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if roleRank[target.Role] >= roleRank[playlist.Role] {
+		return utils.ErrNotEnoughPerms
+	}
+
+	params := queries.MuteUserParams{
+		PlaylistID: playlistId,
+		UserID:     targetId,
+		MutedBy:    userId,
+		Reason:     strings.TrimSpace(reason),
+	}
+	if duration > 0 {
+		params.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(duration), Valid: true}
+	}
+
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		if err := tq.MuteUser(ctx, params); err != nil {
+			return err
+		}
+
+		details := params.Reason
+		if duration > 0 {
+			details = strings.TrimSpace(duration.String() + " " + details)
+		}
+
+		return tq.LogAction(ctx, queries.LogActionParams{
+			PlaylistID:   playlistId,
+			ActorID:      userId,
+			Action:       dto.ActionMute,
+			TargetUserID: pgtype.Int8{Int64: targetId, Valid: true},
+			Details:      details,
+		})
+	})
+}
+
+/*
+Unmute - снять запрет. Как и в Mute, роль заглушённого должна быть ниже своей,
+а запрет, поставленный участником с ролью выше (модератор - владельцем), снять нельзя.
+Запрет бывшего участника снимается без проверки его роли
+*/
+func (s *Moderation) Unmute(ctx context.Context, playlistId string, targetId int64, userId int64) error {
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapMute); err != nil {
+		return err
+	}
+
+	target, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     targetId,
+	})
+	switch {
+	case err == nil:
+		if roleRank[target.Role] >= roleRank[playlist.Role] {
+			return utils.ErrNotEnoughPerms
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return err
+	}
+
+	mute, err := rq.GetActiveMute(ctx, queries.GetActiveMuteParams{
+		PlaylistID: playlistId,
+		UserID:     targetId,
+	})
+	switch {
+	case err == nil:
+		if mute.MutedBy != userId {
+			mutedBy, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+				PlaylistID: playlistId,
+				UserID:     mute.MutedBy,
+			})
+			if err == nil && roleRank[mutedBy.Role] > roleRank[playlist.Role] {
+				return utils.ErrNotEnoughPerms
+			}
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return err
+	}
+
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		removed, err := tq.UnmuteUser(ctx, queries.UnmuteUserParams{
+			PlaylistID: playlistId,
+			UserID:     targetId,
+		})
+		if err != nil {
+			return err
+		}
+
+		if removed == 0 {
+			return pgx.ErrNoRows
+		}
+
+		return tq.LogAction(ctx, queries.LogActionParams{
+			PlaylistID:   playlistId,
+			ActorID:      userId,
+			Action:       dto.ActionUnmute,
+			TargetUserID: pgtype.Int8{Int64: targetId, Valid: true},
+		})
+	})
+}
+
+// Mutes - действующие запреты в плейлисте
+func (s *Moderation) Mutes(ctx context.Context, playlistId string, userId int64) ([]dto.Mute, error) {
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapMute); err != nil {
+		return nil, err
+	}
+
+	mutes, err := rq.GetPlaylistMutes(ctx, playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.Mute, len(mutes))
+	for i, mute := range mutes {
+		result[i] = dto.Mute{
+			User: dto.User{
+				Id:        mute.UserID,
+				Name:      dto.DisplayName(mute.UserID, mute.FirstName, mute.LastName, mute.Username),
+				FirstName: mute.FirstName,
+				LastName:  mute.LastName,
+				Username:  mute.Username,
+			},
+			MutedBy:   mute.MutedBy,
+			Reason:    mute.Reason,
+			ExpiresAt: timePtr(mute.ExpiresAt),
+			CreatedAt: mute.CreatedAt.Time,
+		}
+	}
+
+	return result, nil
+}
+
+// checkMuted - вернуть utils.ErrMuted, если юзер заглушён в плейлисте
+func checkMuted(ctx context.Context, rq *queries.Queries, playlistId string, userId int64) error {
+	_, err := rq.GetActiveMute(ctx, queries.GetActiveMuteParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err == nil {
+		return utils.ErrMuted
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+
+	return err
+}
@@ -2,21 +2,25 @@ package service
 
 import (
 	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
 	"backend/internal/transport/bot/models"
 	"backend/pkg/utils"
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Permission struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	access *Access
 }
 
-func NewPermission(pool *pgxpool.Pool) *Permission {
-	return &Permission{pool: pool}
+func NewPermission(pool *pgxpool.Pool, access *Access) *Permission {
+	return &Permission{pool: pool, access: access}
 }
 
 func (s *Permission) Add(ctx context.Context, role queries.PlaylistRole, playlist string, userId int64) error {
@@ -34,6 +38,10 @@ func (s *Permission) Add(ctx context.Context, role queries.PlaylistRole, playlis
 			}
 		}
 
+		if err := demoteOwner(ctx, tq, role, playlist, userId); err != nil {
+			return err
+		}
+
 		return tq.CreateRole(ctx, queries.CreateRoleParams{
 			Role:       role,
 			UserID:     userId,
@@ -47,18 +55,28 @@ func (s *Permission) AddGroup(ctx context.Context, playlist string, users []mode
 
 	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
 		for _, user := range users {
-			_, err := rq.GetUserById(ctx, user.UserID)
-			if err != nil {
-				if !errors.Is(err, pgx.ErrNoRows) {
+			if user.Profile.ID != 0 {
+				if err := upsertUser(ctx, tq, user.Profile); err != nil {
 					return err
 				}
-				err = tq.CreateUser(ctx, user.UserID)
+			} else {
+				_, err := rq.GetUserById(ctx, user.UserID)
 				if err != nil {
-					return err
+					if !errors.Is(err, pgx.ErrNoRows) {
+						return err
+					}
+					err = tq.CreateUser(ctx, user.UserID)
+					if err != nil {
+						return err
+					}
 				}
 			}
 
-			err = tq.CreateRole(ctx, queries.CreateRoleParams{
+			if err := demoteOwner(ctx, tq, user.NewRole, playlist, user.UserID); err != nil {
+				return err
+			}
+
+			err := tq.CreateRole(ctx, queries.CreateRoleParams{
 				Role:       user.NewRole,
 				UserID:     user.UserID,
 				PlaylistID: playlist,
@@ -72,8 +90,28 @@ func (s *Permission) AddGroup(ctx context.Context, playlist string, users []mode
 	})
 }
 
+// Remove - удалить участника. Владельца можно удалить, только если владение перейдёт старейшему модератору
 func (s *Permission) Remove(ctx context.Context, playlist string, userId int64) error {
 	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		member, err := tq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+			PlaylistID: playlist,
+			UserID:     userId,
+		})
+		if err != nil {
+			return err
+		}
+
+		if member.Role == queries.PlaylistRoleOwner {
+			successorId, err := replaceOwner(ctx, tq, playlist, userId)
+			if err != nil {
+				return err
+			}
+			if successorId == 0 {
+				return fmt.Errorf("%w: no moderator to pass the playlist to", utils.ErrInvalidInput)
+			}
+			return nil
+		}
+
 		return tq.DeleteRole(ctx, queries.DeleteRoleParams{
 			PlaylistID: playlist,
 			UserID:     userId,
@@ -81,8 +119,29 @@ func (s *Permission) Remove(ctx context.Context, playlist string, userId int64)
 	})
 }
 
+/*
+Edit - сменить роль участника. Владелец роль не теряет: сменить его можно только назначив другого владельца.
+Понижение владельца в Telegram намеренно не применяется - владелец, назначенный в приложении, важнее роли в чате,
+как и в Sync. В этом случае возвращается utils.ErrOwnerKept, роль в плейлисте и в чате расходятся
+*/
 func (s *Permission) Edit(ctx context.Context, role queries.PlaylistRole, playlist string, userId int64) error {
 	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		member, err := tq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+			PlaylistID: playlist,
+			UserID:     userId,
+		})
+		if err != nil {
+			return err
+		}
+
+		if member.Role == queries.PlaylistRoleOwner && role != queries.PlaylistRoleOwner {
+			return utils.ErrOwnerKept
+		}
+
+		if err := demoteOwner(ctx, tq, role, playlist, userId); err != nil {
+			return err
+		}
+
 		return tq.EditRole(ctx, queries.EditRoleParams{
 			Role:       role,
 			PlaylistID: playlist,
@@ -99,3 +158,269 @@ func (s *Permission) Get(ctx context.Context, userId int64, role queries.Playlis
 		UserID: userId,
 	})
 }
+
+/*
+Transfer - передать владение другому участнику. Нужно право manage_members: по умолчанию оно только у владельца,
+но владелец может выдать его модераторам. Бывший владелец становится модератором
+*/
+func (s *Permission) Transfer(ctx context.Context, playlist string, targetId int64, userId int64) error {
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		actor, err := tq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+			PlaylistID: playlist,
+			UserID:     userId,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := s.access.Require(ctx, playlist, actor.Role, dto.CapManageMembers); err != nil {
+			return err
+		}
+
+		target, err := tq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+			PlaylistID: playlist,
+			UserID:     targetId,
+		})
+		if err != nil {
+			return err
+		}
+
+		if target.Role == queries.PlaylistRoleOwner {
+			return fmt.Errorf("%w: already the owner", utils.ErrInvalidInput)
+		}
+
+		// сначала понизить владельца: в плейлисте не может быть двух владельцев даже внутри транзакции
+		if err := demoteOwner(ctx, tq, queries.PlaylistRoleOwner, playlist, targetId); err != nil {
+			return err
+		}
+
+		if err := tq.EditRole(ctx, queries.EditRoleParams{
+			PlaylistID: playlist,
+			UserID:     targetId,
+			Role:       queries.PlaylistRoleOwner,
+		}); err != nil {
+			return err
+		}
+
+		return tq.LogAction(ctx, queries.LogActionParams{
+			PlaylistID:   playlist,
+			ActorID:      userId,
+			Action:       dto.ActionTransfer,
+			TargetUserID: pgtype.Int8{Int64: targetId, Valid: true},
+		})
+	})
+}
+
+// Leave - выйти из плейлиста. Владелец сначала должен передать владение
+func (s *Permission) Leave(ctx context.Context, playlist string, userId int64) error {
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		member, err := tq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+			PlaylistID: playlist,
+			UserID:     userId,
+		})
+		if err != nil {
+			return err
+		}
+
+		if member.Role == queries.PlaylistRoleOwner {
+			return fmt.Errorf("%w: owner must transfer the playlist before leaving", utils.ErrInvalidInput)
+		}
+
+		return tq.DeleteRole(ctx, queries.DeleteRoleParams{
+			PlaylistID: playlist,
+			UserID:     userId,
+		})
+	})
+}
+
+/*
+Sync - привести участников плейлиста к списку из Telegram одной транзакцией. Роли берутся из Telegram,
+кроме владельца: если текущий владелец всё ещё в группе, он остаётся владельцем (его могли назначить в приложении),
+а владелец группы в Telegram получает модератора. Ушедший из группы владелец удаляется, только если его место
+занимает владелец группы в Telegram или старейший модератор, иначе он остаётся владельцем.
+
+complete == false - Telegram вернул не всех участников: тогда участники только добавляются и меняют роли,
+отсутствующие в списке не удаляются, а владелец не меняется
+*/
+func (s *Permission) Sync(ctx context.Context, playlist string, users []models.ParticipantData, complete bool) (models.SyncDiff, error) {
+	var diff models.SyncDiff
+
+	// пустой список - скорее ошибка чтения из Telegram, чем пустая группа: не удаляем всех участников
+	if len(users) == 0 {
+		return diff, fmt.Errorf("%w: no members to sync", utils.ErrInvalidInput)
+	}
+
+	err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		members, err := tq.GetPlaylistMembers(ctx, playlist)
+		if err != nil {
+			return err
+		}
+
+		current := make(map[int64]queries.PlaylistRole, len(members))
+		var ownerId int64
+		for _, member := range members {
+			current[member.ID] = member.Role
+			if member.Role == queries.PlaylistRoleOwner {
+				ownerId = member.ID
+			}
+		}
+
+		telegram := make(map[int64]models.ParticipantData, len(users))
+		for _, user := range users {
+			// один юзер может прийти дважды (обычный список и список админов) - берём старшую роль
+			if prev, ok := telegram[user.UserID]; ok && roleRank[prev.NewRole] >= roleRank[user.NewRole] {
+				continue
+			}
+			telegram[user.UserID] = user
+		}
+
+		_, keepOwner := telegram[ownerId]
+		// в неполном списке владельца может просто не быть
+		if !complete && ownerId != 0 {
+			keepOwner = true
+		}
+		ownerReplaced := false
+		for _, user := range telegram {
+			switch {
+			case keepOwner && user.UserID == ownerId:
+				user.NewRole = queries.PlaylistRoleOwner
+			case keepOwner && user.NewRole == queries.PlaylistRoleOwner:
+				user.NewRole = queries.PlaylistRoleModerator
+			}
+
+			role, ok := current[user.UserID]
+			if ok && role == user.NewRole {
+				continue
+			}
+
+			if user.Profile.ID != 0 {
+				if err := upsertUser(ctx, tq, user.Profile); err != nil {
+					return err
+				}
+			} else if _, err := tq.GetUserById(ctx, user.UserID); err != nil {
+				if !errors.Is(err, pgx.ErrNoRows) {
+					return err
+				}
+				if err := tq.CreateUser(ctx, user.UserID); err != nil {
+					return err
+				}
+			}
+
+			if err := demoteOwner(ctx, tq, user.NewRole, playlist, user.UserID); err != nil {
+				return err
+			}
+			if user.NewRole == queries.PlaylistRoleOwner {
+				ownerReplaced = true
+			}
+
+			if !ok {
+				if err := tq.CreateRole(ctx, queries.CreateRoleParams{
+					Role:       user.NewRole,
+					UserID:     user.UserID,
+					PlaylistID: playlist,
+				}); err != nil {
+					return err
+				}
+				diff.Added = append(diff.Added, user)
+				continue
+			}
+
+			if err := tq.EditRole(ctx, queries.EditRoleParams{
+				Role:       user.NewRole,
+				PlaylistID: playlist,
+				UserID:     user.UserID,
+			}); err != nil {
+				return err
+			}
+			user.PrevRole = role
+			diff.Edited = append(diff.Edited, user)
+		}
+
+		if !complete {
+			return nil
+		}
+
+		for userId, role := range current {
+			if _, ok := telegram[userId]; ok {
+				continue
+			}
+
+			// владельца без замены не удаляем, им займёмся после остальных
+			if userId == ownerId && !ownerReplaced {
+				continue
+			}
+
+			if err := tq.DeleteRole(ctx, queries.DeleteRoleParams{
+				PlaylistID: playlist,
+				UserID:     userId,
+			}); err != nil {
+				return err
+			}
+			diff.Removed = append(diff.Removed, models.ParticipantData{PrevRole: role, UserID: userId})
+		}
+
+		if ownerId == 0 || keepOwner || ownerReplaced {
+			return nil
+		}
+
+		successorId, err := replaceOwner(ctx, tq, playlist, ownerId)
+		if err != nil || successorId == 0 {
+			return err
+		}
+
+		diff.Removed = append(diff.Removed, models.ParticipantData{PrevRole: queries.PlaylistRoleOwner, UserID: ownerId})
+		diff.Edited = append(diff.Edited, models.ParticipantData{
+			PrevRole: queries.PlaylistRoleModerator,
+			NewRole:  queries.PlaylistRoleOwner,
+			UserID:   successorId,
+		})
+
+		return nil
+	})
+
+	return diff, err
+}
+
+// demoteOwner - перед назначением нового владельца понизить текущего до модератора
+func demoteOwner(ctx context.Context, tq *queries.Queries, role queries.PlaylistRole, playlist string, userId int64) error {
+	if role != queries.PlaylistRoleOwner {
+		return nil
+	}
+
+	return tq.DemoteOwner(ctx, queries.DemoteOwnerParams{
+		PlaylistID: playlist,
+		UserID:     userId,
+	})
+}
+
+/*
+replaceOwner - удалить владельца, передав владение старейшему модератору.
+Возвращает ID нового владельца, 0 - модераторов нет и владелец остаётся на месте
+*/
+func replaceOwner(ctx context.Context, tq *queries.Queries, playlist string, ownerId int64) (int64, error) {
+	successorId, err := tq.GetOwnerSuccessor(ctx, playlist)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// сначала удалить владельца: в плейлисте не может быть двух владельцев даже внутри транзакции
+	if err := tq.DeleteRole(ctx, queries.DeleteRoleParams{
+		PlaylistID: playlist,
+		UserID:     ownerId,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := tq.EditRole(ctx, queries.EditRoleParams{
+		PlaylistID: playlist,
+		UserID:     successorId,
+		Role:       queries.PlaylistRoleOwner,
+	}); err != nil {
+		return 0, err
+	}
+
+	return successorId, nil
+}
@@ -2,23 +2,39 @@ package service
 
 import (
 	"backend/internal/infra/queries"
+	"backend/internal/interfaces"
 	"backend/internal/transport/api/dto"
+	"backend/pkg/i18n"
+	"backend/pkg/images"
 	"backend/pkg/utils"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/oklog/ulid/v2"
 )
 
 type Playlist struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	access  *Access
+	storage interfaces.Storage
+	images  *Images
 }
 
-func NewPlaylist(pool *pgxpool.Pool) *Playlist {
-	return &Playlist{pool: pool}
+func NewPlaylist(pool *pgxpool.Pool, access *Access, storage interfaces.Storage, images *Images) *Playlist {
+	return &Playlist{pool: pool, access: access, storage: storage, images: images}
 }
 
-func (s *Playlist) Create(ctx context.Context, title string, playlistType queries.PlaylistType, telegramId int64) (dto.Playlist, error) {
+// Create - создать плейлист. telegramId - чат группы (0 - без группы), topicId - тема форума в ней (0 - вся группа)
+func (s *Playlist) Create(ctx context.Context, title string, playlistType queries.PlaylistType, telegramId int64, topicId int) (dto.Playlist, error) {
 	id := ulid.Make().String()
 
 	err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
@@ -31,6 +47,7 @@ func (s *Playlist) Create(ctx context.Context, title string, playlistType querie
 			Type:          playlistType,
 			ExternalID:    "",
 			TelegramID:    telegramId,
+			TopicID:       int32(topicId),
 		})
 	})
 	if err != nil {
@@ -38,15 +55,23 @@ func (s *Playlist) Create(ctx context.Context, title string, playlistType querie
 	}
 
 	return dto.Playlist{
-		Id:    id,
-		Title: title,
-		Type:  string(playlistType),
+		Id:      id,
+		Title:   title,
+		Type:    string(playlistType),
+		TopicId: topicId,
 	}, nil
 }
 
-func (s *Playlist) GetByGroup(ctx context.Context, telegramId int64) (dto.Playlist, error) {
+/*
+GetByGroup - плейлист темы topicId в группе telegramId. У темы без своего плейлиста - плейлист всей группы (topicId == 0).
+Чтобы отличить, смотри TopicId результата
+*/
+func (s *Playlist) GetByGroup(ctx context.Context, telegramId int64, topicId int) (dto.Playlist, error) {
 	rq := queries.New(s.pool)
-	playlist, err := rq.GetGroupPlaylist(ctx, telegramId)
+	playlist, err := rq.GetGroupPlaylist(ctx, queries.GetGroupPlaylistParams{
+		TelegramID: telegramId,
+		TopicID:    int32(topicId),
+	})
 	if err != nil {
 		return dto.Playlist{}, err
 	}
@@ -64,7 +89,7 @@ func (s *Playlist) GetByGroup(ctx context.Context, telegramId int64) (dto.Playli
 			Authors:   dbTrack.Authors,
 			Explicit:  dbTrack.Explicit,
 			Length:    dbTrack.Length,
-			Thumbnail: dbTrack.Thumbnail,
+			Thumbnail: s.images.URL(dbTrack.Thumbnail),
 		}
 	}
 
@@ -83,9 +108,64 @@ func (s *Playlist) GetByGroup(ctx context.Context, telegramId int64) (dto.Playli
 		AllowedCount: int(allowedCount),
 		Role:         "",
 		Type:         string(playlist.Type),
+		Language:     playlist.Language,
+		TopicId:      int(playlist.TopicID),
 	}, nil
 }
 
+/*
+Migrate - перенести плейлист обычной группы на супергруппу, в которую её превратили (у неё новый ID).
+У обычных групп нет тем, поэтому переносится единственный плейлист всей группы.
+migrated == false - переносить нечего: у супергруппы уже есть плейлист (возвращается его ID) или у группы его не было (pgx.ErrNoRows)
+*/
+func (s *Playlist) Migrate(ctx context.Context, oldTelegramId, newTelegramId int64) (string, bool, error) {
+	var playlistId string
+	var migrated bool
+
+	err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		existing, err := tq.GetGroupPlaylist(ctx, queries.GetGroupPlaylistParams{TelegramID: newTelegramId})
+		if err == nil {
+			playlistId = existing.ID
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		playlistId, err = tq.MigratePlaylist(ctx, queries.MigratePlaylistParams{
+			NewID: newTelegramId,
+			OldID: oldTelegramId,
+		})
+		migrated = err == nil
+
+		return err
+	})
+
+	return playlistId, migrated, err
+}
+
+// Groups - плейлисты групп: ID плейлиста -> ID чата
+func (s *Playlist) Groups(ctx context.Context) (map[string]int64, error) {
+	rq := queries.New(s.pool)
+
+	playlists, err := rq.GetGroupPlaylists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(playlists))
+	for _, playlist := range playlists {
+		result[playlist.ID] = playlist.TelegramID
+	}
+
+	return result, nil
+}
+
+// ChatPlaylists - ID всех плейлистов группы: всей группы и её тем
+func (s *Playlist) ChatPlaylists(ctx context.Context, telegramId int64) ([]string, error) {
+	return queries.New(s.pool).GetChatPlaylists(ctx, telegramId)
+}
+
 func (s *Playlist) GetById(ctx context.Context, playlistId string, userId int64) (dto.Playlist, error) {
 	rq := queries.New(s.pool)
 	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
@@ -96,21 +176,19 @@ func (s *Playlist) GetById(ctx context.Context, playlistId string, userId int64)
 		return dto.Playlist{}, err
 	}
 
-	tracks := make([]dto.Track, len(playlist.Tracks))
-	for i, entity := range playlist.Tracks {
-		dbTrack, err := rq.GetTrackById(ctx, entity)
-		if err != nil {
-			return dto.Playlist{}, err
-		}
+	tracks, err := s.tracks(ctx, rq, playlist.ID)
+	if err != nil {
+		return dto.Playlist{}, err
+	}
 
-		tracks[i] = dto.Track{
-			Id:        dbTrack.ID,
-			Title:     dbTrack.Title,
-			Authors:   dbTrack.Authors,
-			Explicit:  dbTrack.Explicit,
-			Length:    dbTrack.Length,
-			Thumbnail: dbTrack.Thumbnail,
-		}
+	members, err := s.members(ctx, rq, playlist.ID)
+	if err != nil {
+		return dto.Playlist{}, err
+	}
+
+	capabilities, err := s.access.Capabilities(ctx, playlist.ID, playlist.Role)
+	if err != nil {
+		return dto.Playlist{}, err
 	}
 
 	count := playlist.Count.Int32
@@ -128,9 +206,69 @@ func (s *Playlist) GetById(ctx context.Context, playlistId string, userId int64)
 		AllowedCount: int(allowedCount),
 		Role:         playlist.Role,
 		Type:         string(playlist.Type),
+		Language:     playlist.Language,
+		TopicId:      int(playlist.TopicID),
+		Members:      members,
+		Capabilities: capabilities,
 	}, nil
 }
 
+/*
+tracks - треки плейлиста по порядку с именем предложившего. Для импорта из CSV это имя из файла,
+у треков, добавленных до истории предложений, имени нет
+*/
+func (s *Playlist) tracks(ctx context.Context, rq *queries.Queries, playlistId string) ([]dto.Track, error) {
+	rows, err := rq.GetPlaylistTracks(ctx, playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.Track, len(rows))
+	for i, row := range rows {
+		submitter := row.SubmitterName
+		if submitter == "" && row.SubmitterID != 0 {
+			submitter = dto.DisplayName(row.SubmitterID, row.FirstName, row.LastName, row.Username)
+		}
+
+		result[i] = dto.Track{
+			Id:        row.ID,
+			Title:     row.Title,
+			Authors:   row.Authors,
+			Explicit:  row.Explicit,
+			Length:    row.Length,
+			Thumbnail: s.images.URL(row.Thumbnail),
+			Submitter: submitter,
+		}
+	}
+
+	return result, nil
+}
+
+// members - участники плейлиста с именами, сначала владелец и модераторы
+func (s *Playlist) members(ctx context.Context, rq *queries.Queries, playlistId string) ([]dto.Member, error) {
+	rows, err := rq.GetPlaylistMembers(ctx, playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.Member, len(rows))
+	for i, row := range rows {
+		result[i] = dto.Member{
+			User: dto.User{
+				Id:        row.ID,
+				Name:      dto.DisplayName(row.ID, row.FirstName, row.LastName, row.Username),
+				FirstName: row.FirstName,
+				LastName:  row.LastName,
+				Username:  row.Username,
+				PhotoUrl:  row.PhotoUrl,
+			},
+			Role: row.Role,
+		}
+	}
+
+	return result, nil
+}
+
 func (s *Playlist) GetAll(ctx context.Context, userId int64) ([]dto.Playlist, error) {
 	rq := queries.New(s.pool)
 	playlists, err := rq.GetUserPlaylists(ctx, userId)
@@ -155,6 +293,8 @@ func (s *Playlist) GetAll(ctx context.Context, userId int64) ([]dto.Playlist, er
 			AllowedIds:   make([]string, 0),
 			Role:         playlist.Role,
 			Type:         string(playlist.Type),
+			Language:     playlist.Language,
+			TopicId:      int(playlist.TopicID),
 		}
 	}
 
@@ -171,38 +311,211 @@ func (s *Playlist) Rename(ctx context.Context, playlistId, title string, userId
 		return err
 	}
 
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapEditSettings); err != nil {
+		return err
+	}
+
 	playlist.Title = title
 
 	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
 		return tq.EditPlaylist(ctx, queries.EditPlaylistParams{
 			ID:    playlist.ID,
-			Title: playlist.Title,
+			Title: pgtype.Text{String: playlist.Title, Valid: true},
 		})
 	})
 }
 
-func (s *Playlist) UpdatePhoto(ctx context.Context, playlistId, thumbnail string, userId int64) error {
+// coverSizes - ширины квадратных обложек, первая - основная, на неё указывает thumbnail
+var coverSizes = []int{640, 160}
+
+// UpdatePhoto - обложка из фото группы, photo == nil - убрать обложку. Вызывается ботом, поэтому права не проверяются
+func (s *Playlist) UpdatePhoto(ctx context.Context, playlistId string, photo []byte) error {
+	if photo == nil {
+		return s.setCover(ctx, playlistId, "")
+	}
+
+	_, err := s.storeCover(ctx, playlistId, photo)
+	return err
+}
+
+// UploadCover - загрузить свою обложку (JPEG, PNG или GIF до dto.CoverMaxBytes). Возвращает ссылку на основной размер
+func (s *Playlist) UploadCover(ctx context.Context, playlistId string, image []byte, userId int64) (string, error) {
 	rq := queries.New(s.pool)
 	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
 		PlaylistID: playlistId,
 		UserID:     userId,
 	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapEditSettings); err != nil {
+		return "", err
+	}
+
+	if len(image) > dto.CoverMaxBytes {
+		return "", fmt.Errorf("%w: cover must be at most %d MB", utils.ErrInvalidInput, dto.CoverMaxBytes>>20)
+	}
+
+	return s.storeCover(ctx, playlistId, image)
+}
+
+/*
+storeCover - перекодировать картинку в квадратные JPEG всех coverSizes, сохранить в хранилище и сделать обложкой.
+Файлы лежат в playlists/<id>/<хеш исходника>/<ширина>.jpg: у новой обложки всегда новая ссылка, поэтому её можно кешировать навсегда
+*/
+func (s *Playlist) storeCover(ctx context.Context, playlistId string, data []byte) (string, error) {
+	img, err := images.Decode(data)
+	if err != nil {
+		if errors.Is(err, images.ErrUnsupported) || errors.Is(err, images.ErrTooLarge) {
+			return "", fmt.Errorf("%w: %w", utils.ErrInvalidInput, err)
+		}
+		return "", fmt.Errorf("%w: cannot decode image: %w", utils.ErrInvalidInput, err)
+	}
+	img = images.Square(img)
+
+	sum := sha256.Sum256(data)
+	dir := "playlists/" + playlistId + "/" + hex.EncodeToString(sum[:8])
+
+	for _, size := range coverSizes {
+		encoded, err := images.EncodeJPEG(images.Resize(img, size))
+		if err != nil {
+			return "", err
+		}
+
+		if err := s.storage.Put(ctx, dir+"/"+strconv.Itoa(size)+".jpg", encoded, "image/jpeg"); err != nil {
+			return "", err
+		}
+	}
+
+	thumbnail := s.storage.URL(dir + "/" + strconv.Itoa(coverSizes[0]) + ".jpg")
+
+	return thumbnail, s.setCover(ctx, playlistId, thumbnail)
+}
+
+// setCover - сменить ссылку на обложку и удалить файлы прошлой обложки, если она лежала в хранилище
+func (s *Playlist) setCover(ctx context.Context, playlistId, thumbnail string) error {
+	var previous string
+	err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		playlist, err := tq.GetPlaylistById(ctx, playlistId)
+		if err != nil {
+			return err
+		}
+		previous = playlist.Thumbnail
+
+		return tq.EditPlaylist(ctx, queries.EditPlaylistParams{
+			ID:        playlistId,
+			Thumbnail: pgtype.Text{String: thumbnail, Valid: true},
+		})
+	})
 	if err != nil {
 		return err
 	}
 
-	playlist.Thumbnail = thumbnail
+	// старые файлы удаляются после коммита: если удаление не удалось, остаются только лишние файлы
+	key, ok := strings.CutPrefix(previous, s.storage.URL(""))
+	if previous == thumbnail || !ok || key == "" {
+		return nil
+	}
+
+	for _, size := range coverSizes {
+		if err := s.storage.Delete(ctx, path.Dir(key)+"/"+strconv.Itoa(size)+".jpg"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetLanguage - язык бота в группе плейлиста. Вызывается ботом, права проверяет он
+func (s *Playlist) SetLanguage(ctx context.Context, playlistId, language string) error {
+	if !i18n.Supported(language) {
+		return fmt.Errorf("%w: unsupported language %s", utils.ErrInvalidInput, language)
+	}
 
 	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
-		return tq.EditPlaylist(ctx, queries.EditPlaylistParams{
-			ID:        playlist.ID,
-			Thumbnail: playlist.Thumbnail,
+		return tq.SetPlaylistLanguage(ctx, queries.SetPlaylistLanguageParams{
+			ID:       playlistId,
+			Language: language,
 		})
 	})
 }
 
+// GroupLanguage - язык плейлиста темы (или всей группы, см. GetByGroup) без загрузки треков, для ответов бота
+func (s *Playlist) GroupLanguage(ctx context.Context, telegramId int64, topicId int) (string, error) {
+	return queries.New(s.pool).GetGroupLanguage(ctx, queries.GetGroupLanguageParams{
+		TelegramID: telegramId,
+		TopicID:    int32(topicId),
+	})
+}
+
 func (s *Playlist) Delete(ctx context.Context, playlistId string) error {
 	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
 		return tq.DeletePlaylist(ctx, playlistId)
 	})
 }
+
+// Export - получить плейлист для экспорта. По умолчанию только разрешённые треки в порядке проигрывания
+func (s *Playlist) Export(ctx context.Context, playlistId string, includePending bool, userId int64) (dto.Playlist, error) {
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return dto.Playlist{}, err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapExport); err != nil {
+		return dto.Playlist{}, err
+	}
+
+	ids := playlist.AllowedTracks
+	if includePending {
+		ids = playlist.Tracks
+	}
+
+	dbTracks, err := rq.GetTracksByIds(ctx, ids)
+	if err != nil {
+		return dto.Playlist{}, err
+	}
+
+	byId := make(map[string]queries.Track, len(dbTracks))
+	for _, track := range dbTracks {
+		byId[track.ID] = track
+	}
+
+	tracks := make([]dto.Track, 0, len(ids))
+	length := 0
+	for _, id := range ids {
+		dbTrack, ok := byId[id]
+		if !ok {
+			continue
+		}
+
+		tracks = append(tracks, dto.Track{
+			Id:        dbTrack.ID,
+			Title:     dbTrack.Title,
+			Authors:   dbTrack.Authors,
+			Explicit:  dbTrack.Explicit,
+			Length:    dbTrack.Length,
+			Thumbnail: s.images.URL(dbTrack.Thumbnail),
+		})
+		length += int(dbTrack.Length)
+	}
+
+	return dto.Playlist{
+		Id:           playlist.ID,
+		Title:        playlist.Title,
+		Thumbnail:    playlist.Thumbnail,
+		Tracks:       tracks,
+		AllowedIds:   playlist.AllowedTracks,
+		Count:        len(tracks),
+		Length:       length,
+		AllowedCount: int(playlist.AllowedCount.Int32),
+		Role:         playlist.Role,
+		Type:         string(playlist.Type),
+		Language:     playlist.Language,
+		TopicId:      int(playlist.TopicID),
+	}, nil
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
+	"backend/pkg/utils"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+/*
+Sequence - составить порядок проигрывания из разрешённых треков плейлиста. Ничего не сохраняет, для сохранения - Reorder
+
+Голосов за треки пока нет, поэтому при обрезке по длительности приоритет у треков, которые раньше попали в разрешённые
+*/
+func (s *Playlist) Sequence(ctx context.Context, playlistId string, opts dto.SequenceOptions, userId int64) (dto.Sequence, error) {
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return dto.Sequence{}, err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapReorder); err != nil {
+		return dto.Sequence{}, err
+	}
+
+	if opts.First != "" && opts.First == opts.Last {
+		return dto.Sequence{}, fmt.Errorf("%w: first and last tracks are the same", utils.ErrInvalidInput)
+	}
+
+	dbTracks, err := rq.GetTracksByIds(ctx, playlist.AllowedTracks)
+	if err != nil {
+		return dto.Sequence{}, err
+	}
+
+	byId := make(map[string]queries.Track, len(dbTracks))
+	for _, track := range dbTracks {
+		byId[track.ID] = track
+	}
+
+	var first, last *queries.Track
+	for _, pin := range []struct {
+		id     string
+		target **queries.Track
+	}{{opts.First, &first}, {opts.Last, &last}} {
+		if pin.id == "" {
+			continue
+		}
+
+		track, ok := byId[pin.id]
+		if !ok {
+			return dto.Sequence{}, pgx.ErrNoRows
+		}
+
+		if opts.ExcludeExplicit && track.Explicit {
+			return dto.Sequence{}, fmt.Errorf("%w: pinned track %s is explicit", utils.ErrInvalidInput, pin.id)
+		}
+
+		*pin.target = &track
+	}
+
+	budget := opts.TargetLength
+	if first != nil {
+		budget -= int(first.Length)
+	}
+	if last != nil {
+		budget -= int(last.Length)
+	}
+
+	if opts.TargetLength > 0 && budget < 0 {
+		return dto.Sequence{}, fmt.Errorf("%w: pinned tracks are longer than target length", utils.ErrInvalidInput)
+	}
+
+	// порядок allowed_tracks - порядок одобрения, он же приоритет
+	candidates := make([]queries.Track, 0, len(playlist.AllowedTracks))
+	trimmed := make([]string, 0)
+	for _, id := range playlist.AllowedTracks {
+		track, ok := byId[id]
+		if !ok || id == opts.First || id == opts.Last {
+			continue
+		}
+
+		if opts.ExcludeExplicit && track.Explicit {
+			trimmed = append(trimmed, id)
+			continue
+		}
+
+		if opts.TargetLength > 0 {
+			if int(track.Length) > budget {
+				trimmed = append(trimmed, id)
+				continue
+			}
+			budget -= int(track.Length)
+		}
+
+		candidates = append(candidates, track)
+	}
+
+	// seed не задан - случайный; 0 - такой же допустимый seed, как и остальные
+	seed := rand.Uint64()
+	if opts.Seed != nil {
+		seed = *opts.Seed
+	}
+
+	rng := rand.New(rand.NewPCG(seed, seed))
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	conflicts := 0
+	if opts.NoRepeatArtist {
+		candidates, conflicts = spreadArtists(candidates, first, last)
+	}
+
+	ordered := make([]queries.Track, 0, len(candidates)+2)
+	if first != nil {
+		ordered = append(ordered, *first)
+	}
+	ordered = append(ordered, candidates...)
+	if last != nil {
+		ordered = append(ordered, *last)
+	}
+
+	result := dto.Sequence{
+		Seed:      seed,
+		Tracks:    make([]dto.Track, len(ordered)),
+		Trimmed:   trimmed,
+		Conflicts: conflicts,
+	}
+	for i, track := range ordered {
+		result.Tracks[i] = dto.Track{
+			Id:        track.ID,
+			Title:     track.Title,
+			Authors:   track.Authors,
+			Explicit:  track.Explicit,
+			Length:    track.Length,
+			Thumbnail: s.images.URL(track.Thumbnail),
+		}
+		result.Length += int(track.Length)
+	}
+
+	return result, nil
+}
+
+// Reorder - сохранить порядок треков, принятый владельцем. Треки не из списка остаются после него в прежнем порядке
+func (s *Playlist) Reorder(ctx context.Context, playlistId string, trackIds []string, userId int64) error {
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapReorder); err != nil {
+		return err
+	}
+
+	for i, id := range trackIds {
+		if !slices.Contains(playlist.AllowedTracks, id) {
+			return pgx.ErrNoRows
+		}
+
+		if slices.Contains(trackIds[:i], id) {
+			return fmt.Errorf("%w: duplicate track %s", utils.ErrInvalidInput, id)
+		}
+	}
+
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		return tq.EditPlaylist(ctx, queries.EditPlaylistParams{
+			ID:            playlistId,
+			Tracks:        moveToFront(playlist.Tracks, trackIds),
+			AllowedTracks: moveToFront(playlist.AllowedTracks, trackIds),
+		})
+	})
+}
+
+// moveToFront - поставить ids в начало списка, остальные элементы сохраняют порядок
+func moveToFront(list, ids []string) []string {
+	result := make([]string, 0, len(list))
+	result = append(result, ids...)
+	for _, id := range list {
+		if !slices.Contains(ids, id) {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+// spreadArtists - жадно переставить треки так, чтобы у соседних не совпадали исполнители. Возвращает число неразрешённых пар
+func spreadArtists(tracks []queries.Track, first, last *queries.Track) ([]queries.Track, int) {
+	result := make([]queries.Track, 0, len(tracks))
+	rest := slices.Clone(tracks)
+	prev := first
+	conflicts := 0
+
+	for len(rest) > 0 {
+		pick := -1
+		for i := range rest {
+			if prev != nil && sameArtist(*prev, rest[i]) {
+				continue
+			}
+
+			// перед закреплённым последним треком не должен остаться тот же исполнитель
+			if len(rest) == 2 && last != nil && sameArtist(rest[1-i], *last) {
+				continue
+			}
+
+			pick = i
+			break
+		}
+
+		if pick == -1 {
+			pick = 0
+			conflicts++
+		}
+
+		picked := rest[pick]
+		result = append(result, picked)
+		prev = &picked
+		rest = slices.Delete(rest, pick, pick+1)
+	}
+
+	if prev != nil && last != nil && sameArtist(*prev, *last) {
+		conflicts++
+	}
+
+	return result, conflicts
+}
+
+// sameArtist - есть ли у треков общий исполнитель
+func sameArtist(a, b queries.Track) bool {
+	aArtists := splitArtists(a.Authors)
+	for _, artist := range splitArtists(b.Authors) {
+		if slices.Contains(aArtists, artist) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitArtists(authors string) []string {
+	parts := strings.FieldsFunc(strings.ToLower(strings.ReplaceAll(authors, " & ", ",")), func(r rune) bool {
+		return r == ','
+	})
+
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"backend/internal/transport/api/dto"
+
+	"go.uber.org/zap"
+)
+
+// submissionsBuffer - сколько событий может ждать обработки, лишние отбрасываются
+const submissionsBuffer = 64
+
+/*
+Submissions - очередь новых треков на модерации. Track пишет в неё после сохранения трека,
+бот читает и рассылает карточки модерации. Запись не блокирует: если очередь никто не читает, события теряются,
+а потерянные карточки пишутся в лог. Сам трек остаётся на модерации и виден в /pending
+*/
+type Submissions struct {
+	ch     chan dto.Submission
+	logger *zap.Logger
+}
+
+func NewSubmissions(logger *zap.Logger) *Submissions {
+	return &Submissions{ch: make(chan dto.Submission, submissionsBuffer), logger: logger}
+}
+
+// Publish - добавить событие, не дожидаясь читателя
+func (s *Submissions) Publish(submission dto.Submission) {
+	select {
+	case s.ch <- submission:
+	default:
+		s.logger.Warn("moderation card dropped, queue is full: playlistID: " + submission.PlaylistId + ", trackID: " + submission.Track.Id)
+	}
+}
+
+// Listen - канал событий для единственного читателя
+func (s *Submissions) Listen() <-chan dto.Submission {
+	return s.ch
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
+	"backend/pkg/utils"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// ApiTokenPrefix - по префиксу API токен отличается от JWT
+	ApiTokenPrefix = "muse_"
+
+	maxApiTokens = 20
+)
+
+type Token struct {
+	pool *pgxpool.Pool
+}
+
+func NewToken(pool *pgxpool.Pool) *Token {
+	return &Token{pool: pool}
+}
+
+// Create - выпустить API токен. Сам токен возвращается только здесь, в базе хранится его хэш
+func (s *Token) Create(ctx context.Context, name string, scopes, playlists []string, expiresIn int, userId int64) (dto.CreatedApiToken, error) {
+	rq := queries.New(s.pool)
+
+	tokens, err := rq.GetUserApiTokens(ctx, userId)
+	if err != nil {
+		return dto.CreatedApiToken{}, err
+	}
+
+	if len(tokens) >= maxApiTokens {
+		return dto.CreatedApiToken{}, fmt.Errorf("%w: no more than %d active tokens", utils.ErrInvalidInput, maxApiTokens)
+	}
+
+	// ограничить токен можно только плейлистами, в которых юзер состоит
+	for _, playlistId := range playlists {
+		if _, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+			PlaylistID: playlistId,
+			UserID:     userId,
+		}); err != nil {
+			return dto.CreatedApiToken{}, err
+		}
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return dto.CreatedApiToken{}, err
+	}
+	token := ApiTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	params := queries.CreateApiTokenParams{
+		ID:        ulid.Make().String(),
+		UserID:    userId,
+		Name:      strings.TrimSpace(name),
+		TokenHash: hashToken(token),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		Playlists: slices.Compact(slices.Sorted(slices.Values(playlists))),
+	}
+	if expiresIn > 0 {
+		params.ExpiresAt = pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, expiresIn), Valid: true}
+	}
+
+	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		return tq.CreateApiToken(ctx, params)
+	}); err != nil {
+		return dto.CreatedApiToken{}, err
+	}
+
+	return dto.CreatedApiToken{
+		ApiToken: dto.ApiToken{
+			Id:        params.ID,
+			Name:      params.Name,
+			Scopes:    params.Scopes,
+			Playlists: params.Playlists,
+			CreatedAt: time.Now(),
+			ExpiresAt: timePtr(params.ExpiresAt),
+		},
+		Token: token,
+	}, nil
+}
+
+// List - действующие API токены юзера
+func (s *Token) List(ctx context.Context, userId int64) ([]dto.ApiToken, error) {
+	rq := queries.New(s.pool)
+
+	tokens, err := rq.GetUserApiTokens(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.ApiToken, len(tokens))
+	for i, token := range tokens {
+		result[i] = dto.ApiToken{
+			Id:         token.ID,
+			Name:       token.Name,
+			Scopes:     token.Scopes,
+			Playlists:  token.Playlists,
+			CreatedAt:  token.CreatedAt.Time,
+			LastUsedAt: timePtr(token.LastUsedAt),
+			ExpiresAt:  timePtr(token.ExpiresAt),
+		}
+	}
+
+	return result, nil
+}
+
+// Revoke - отозвать API токен
+func (s *Token) Revoke(ctx context.Context, tokenId string, userId int64) error {
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		revoked, err := tq.RevokeApiToken(ctx, queries.RevokeApiTokenParams{
+			ID:     tokenId,
+			UserID: userId,
+		})
+		if err != nil {
+			return err
+		}
+
+		if revoked == 0 {
+			return pgx.ErrNoRows
+		}
+
+		return nil
+	})
+}
+
+// Verify - проверить API токен. Возвращает ID владельца и права токена
+func (s *Token) Verify(ctx context.Context, token string) (int64, dto.TokenGrant, error) {
+	rq := queries.New(s.pool)
+
+	apiToken, err := rq.GetApiTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, dto.TokenGrant{}, utils.ErrInvalidToken
+		}
+		return 0, dto.TokenGrant{}, err
+	}
+
+	if apiToken.RevokedAt.Valid || (apiToken.ExpiresAt.Valid && apiToken.ExpiresAt.Time.Before(time.Now())) {
+		return 0, dto.TokenGrant{}, utils.ErrInvalidToken
+	}
+
+	if err := rq.TouchApiToken(ctx, apiToken.ID); err != nil {
+		return 0, dto.TokenGrant{}, err
+	}
+
+	return apiToken.UserID, dto.TokenGrant{
+		TokenId:   apiToken.ID,
+		Scopes:    apiToken.Scopes,
+		Playlists: apiToken.Playlists,
+	}, nil
+}
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
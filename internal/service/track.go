@@ -11,18 +11,22 @@ import (
 	"slices"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Track struct {
-	pool *pgxpool.Pool
+	pool        *pgxpool.Pool
+	access      *Access
+	submissions *Submissions
+	images      *Images
 
 	youtube interfaces.SearchAPI
 	// spotify SearchAPI
 }
 
-func NewTrack(pool *pgxpool.Pool, ytApi *youtube.API) *Track {
-	return &Track{pool: pool, youtube: ytApi}
+func NewTrack(pool *pgxpool.Pool, ytApi *youtube.API, access *Access, submissions *Submissions, images *Images) *Track {
+	return &Track{pool: pool, youtube: ytApi, access: access, submissions: submissions, images: images}
 }
 
 /*
@@ -67,6 +71,11 @@ func (s *Track) Search(ctx context.Context, query string) ([]dto.Track, error) {
 		return nil, err
 	}
 
+	// в базе хранятся исходные ссылки на обложки, наружу отдаются ссылки через прокси
+	for i := range tracks {
+		tracks[i].Thumbnail = s.images.URL(tracks[i].Thumbnail)
+	}
+
 	return tracks, nil
 }
 
@@ -81,7 +90,7 @@ func (s *Track) GetById(ctx context.Context, id string) (dto.Track, error) {
 		Id:        track.ID,
 		Title:     track.Title,
 		Authors:   track.Authors,
-		Thumbnail: track.Thumbnail,
+		Thumbnail: s.images.URL(track.Thumbnail),
 		Length:    track.Length,
 		Explicit:  track.Explicit,
 	}, nil
@@ -97,8 +106,8 @@ func (s *Track) Approve(ctx context.Context, playlistId, trackId string, userId
 		return err
 	}
 
-	if playlist.Role != queries.PlaylistRoleOwner && playlist.Role != queries.PlaylistRoleModerator {
-		return utils.ErrNotEnoughPerms
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapApprove); err != nil {
+		return err
 	}
 
 	if slices.Contains(playlist.AllowedTracks, trackId) {
@@ -110,10 +119,14 @@ func (s *Track) Approve(ctx context.Context, playlistId, trackId string, userId
 	}
 
 	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
-		return tq.EditPlaylist(ctx, queries.EditPlaylistParams{
+		if err := tq.EditPlaylist(ctx, queries.EditPlaylistParams{
 			ID:            playlistId,
 			AllowedTracks: append(playlist.AllowedTracks, trackId),
-		})
+		}); err != nil {
+			return err
+		}
+
+		return tq.LogAction(ctx, trackAction(playlistId, trackId, dto.ActionApprove, userId))
 	}); err != nil {
 		return err
 	}
@@ -131,8 +144,8 @@ func (s *Track) Decline(ctx context.Context, playlistId, trackId string, userId
 		return err
 	}
 
-	if playlist.Role != queries.PlaylistRoleOwner && playlist.Role != queries.PlaylistRoleModerator {
-		return utils.ErrNotEnoughPerms
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapDecline); err != nil {
+		return err
 	}
 
 	if slices.Contains(playlist.AllowedTracks, trackId) || !slices.Contains(playlist.Tracks, trackId) {
@@ -147,10 +160,14 @@ func (s *Track) Decline(ctx context.Context, playlistId, trackId string, userId
 	}
 
 	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
-		return tq.EditPlaylist(ctx, queries.EditPlaylistParams{
-			ID:            playlistId,
-			AllowedTracks: playlist.AllowedTracks,
-		})
+		if err := tq.EditPlaylist(ctx, queries.EditPlaylistParams{
+			ID:     playlistId,
+			Tracks: playlist.Tracks,
+		}); err != nil {
+			return err
+		}
+
+		return tq.LogAction(ctx, trackAction(playlistId, trackId, dto.ActionDecline, userId))
 	}); err != nil {
 		return err
 	}
@@ -168,34 +185,125 @@ func (s *Track) Submit(ctx context.Context, playlistId, trackId string, userId i
 		return err
 	}
 
-	if _, err := rq.GetTrackById(ctx, trackId); err != nil {
+	track, err := rq.GetTrackById(ctx, trackId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapSubmit); err != nil {
+		return err
+	}
+
+	if err := checkMuted(ctx, rq, playlistId, userId); err != nil {
+		return err
+	}
+
+	// у кого есть право одобрять, тот добавляет трек сразу в разрешённые
+	autoApprove, err := s.access.Can(ctx, playlistId, playlist.Role, dto.CapApprove)
+	if err != nil {
 		return err
 	}
 
 	tracks := playlist.Tracks
 	allowedTracks := playlist.AllowedTracks
-	if (playlist.Role == queries.PlaylistRoleOwner || playlist.Role == queries.PlaylistRoleModerator) && !slices.Contains(allowedTracks, trackId) {
-		tracks = append(tracks, trackId)
+	if autoApprove && !slices.Contains(allowedTracks, trackId) {
+		if !slices.Contains(tracks, trackId) {
+			tracks = append(tracks, trackId)
+		}
 		allowedTracks = append(allowedTracks, trackId)
-	} else if !slices.Contains(tracks, trackId) && playlist.Role == queries.PlaylistRoleViewer {
+	} else if !autoApprove && !slices.Contains(tracks, trackId) {
 		tracks = append(tracks, trackId)
 	} else {
 		return nil
 	}
 
 	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
-		return tq.EditPlaylist(ctx, queries.EditPlaylistParams{
+		if err := tq.EditPlaylist(ctx, queries.EditPlaylistParams{
 			ID:            playlistId,
 			Tracks:        tracks,
 			AllowedTracks: allowedTracks,
+		}); err != nil {
+			return err
+		}
+
+		return tq.AddSubmission(ctx, queries.AddSubmissionParams{
+			PlaylistID: playlistId,
+			TrackID:    trackId,
+			UserID:     userId,
 		})
 	}); err != nil {
 		return err
 	}
 
+	if !autoApprove {
+		s.submissions.Publish(dto.Submission{
+			PlaylistId:    playlistId,
+			PlaylistTitle: playlist.Title,
+			TelegramId:    playlist.TelegramID,
+			TopicId:       int(playlist.TopicID),
+			Language:      playlist.Language,
+			Track: dto.Track{
+				Id:        track.ID,
+				Title:     track.Title,
+				Authors:   track.Authors,
+				Thumbnail: s.images.URL(track.Thumbnail),
+				Length:    track.Length,
+				Explicit:  track.Explicit,
+			},
+			UserId: userId,
+		})
+	}
+
 	return nil
 }
 
+// Mine - последние треки, предложенные юзером в плейлист, со статусом модерации
+func (s *Track) Mine(ctx context.Context, playlistId string, limit int32, userId int64) ([]dto.SubmissionStatus, error) {
+	rq := queries.New(s.pool)
+	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	submissions, err := rq.GetUserSubmissions(ctx, queries.GetUserSubmissionsParams{
+		PlaylistID: playlistId,
+		UserID:     userId,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.SubmissionStatus, len(submissions))
+	for i, submission := range submissions {
+		// отклонённый трек удаляется из плейлиста, запись о том, кто его предложил, остаётся
+		status := dto.StatusDeclined
+		if slices.Contains(playlist.AllowedTracks, submission.TrackID) {
+			status = dto.StatusApproved
+		} else if slices.Contains(playlist.Tracks, submission.TrackID) {
+			status = dto.StatusPending
+		}
+
+		result[i] = dto.SubmissionStatus{
+			Track: dto.Track{
+				Id:        submission.TrackID,
+				Title:     submission.Title,
+				Authors:   submission.Authors,
+				Thumbnail: s.images.URL(submission.Thumbnail),
+				Length:    submission.Length,
+				Explicit:  submission.Explicit,
+			},
+			Status:      status,
+			SubmittedAt: submission.CreatedAt.Time,
+		}
+	}
+
+	return result, nil
+}
+
 func (s *Track) Unapprove(ctx context.Context, playlistId, trackId string, userId int64) error {
 	rq := queries.New(s.pool)
 	playlist, err := rq.GetUserPlaylistById(ctx, queries.GetUserPlaylistByIdParams{
@@ -206,8 +314,8 @@ func (s *Track) Unapprove(ctx context.Context, playlistId, trackId string, userI
 		return err
 	}
 
-	if playlist.Role != queries.PlaylistRoleOwner && playlist.Role != queries.PlaylistRoleModerator {
-		return utils.ErrNotEnoughPerms
+	if err := s.access.Require(ctx, playlistId, playlist.Role, dto.CapUnapprove); err != nil {
+		return err
 	}
 
 	if !slices.Contains(playlist.AllowedTracks, trackId) || !slices.Contains(playlist.Tracks, trackId) {
@@ -222,13 +330,27 @@ func (s *Track) Unapprove(ctx context.Context, playlistId, trackId string, userI
 	}
 
 	if err := utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
-		return tq.EditPlaylist(ctx, queries.EditPlaylistParams{
+		if err := tq.EditPlaylist(ctx, queries.EditPlaylistParams{
 			ID:            playlistId,
 			AllowedTracks: playlist.AllowedTracks,
-		})
+		}); err != nil {
+			return err
+		}
+
+		return tq.LogAction(ctx, trackAction(playlistId, trackId, dto.ActionUnapprove, userId))
 	}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// trackAction - запись истории модерации о решении по треку
+func trackAction(playlistId, trackId, action string, userId int64) queries.LogActionParams {
+	return queries.LogActionParams{
+		PlaylistID: playlistId,
+		ActorID:    userId,
+		Action:     action,
+		TrackID:    pgtype.Text{String: trackId, Valid: true},
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
 	"backend/pkg/utils"
 	"context"
 
@@ -41,3 +42,46 @@ func (s *User) GetByID(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// Get - профиль юзера
+func (s *User) Get(ctx context.Context, id int64) (dto.User, error) {
+	rq := queries.New(s.pool)
+
+	user, err := rq.GetUserById(ctx, id)
+	if err != nil {
+		return dto.User{}, err
+	}
+
+	return userFromRow(user), nil
+}
+
+func userFromRow(user queries.User) dto.User {
+	return dto.User{
+		Id:           user.ID,
+		Name:         dto.DisplayName(user.ID, user.FirstName, user.LastName, user.Username),
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		Username:     user.Username,
+		LanguageCode: user.LanguageCode,
+		PhotoUrl:     user.PhotoUrl,
+	}
+}
+
+// Upsert - создать юзера или обновить его профиль данными из Telegram
+func (s *User) Upsert(ctx context.Context, profile dto.TelegramData) error {
+	return utils.ExecInTx(ctx, s.pool, func(tq *queries.Queries) error {
+		return upsertUser(ctx, tq, profile)
+	})
+}
+
+// upsertUser - сохранить профиль. Язык и фото не затираются пустыми значениями: бот их не получает
+func upsertUser(ctx context.Context, tq *queries.Queries, profile dto.TelegramData) error {
+	return tq.UpsertUser(ctx, queries.UpsertUserParams{
+		ID:           profile.ID,
+		FirstName:    profile.FirstName,
+		LastName:     profile.LastName,
+		Username:     profile.Username,
+		LanguageCode: profile.LanguageCode,
+		PhotoUrl:     profile.PhotoUrl,
+	})
+}
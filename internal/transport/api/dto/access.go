@@ -0,0 +1,43 @@
+package dto
+
+import "backend/internal/infra/queries"
+
+// Capability - действие в плейлисте, которое может быть разрешено роли
+type Capability string
+
+const (
+	CapSubmit        Capability = "submit"
+	CapApprove       Capability = "approve"
+	CapDecline       Capability = "decline"
+	CapUnapprove     Capability = "unapprove"
+	CapReorder       Capability = "reorder"
+	CapExport        Capability = "export"
+	CapImport        Capability = "import"
+	CapMute          Capability = "mute"
+	CapManageMembers Capability = "manage_members"
+	CapEditSettings  Capability = "edit_settings"
+)
+
+type CapabilityOverride struct {
+	Role       queries.PlaylistRole `json:"role" example:"viewer"`
+	Capability Capability           `json:"capability" example:"submit"`
+	Allowed    bool                 `json:"allowed"`
+}
+
+type CapabilityMatrix struct {
+	Roles     map[queries.PlaylistRole][]Capability `json:"roles"`     // effective capabilities with overrides applied
+	Overrides []CapabilityOverride                  `json:"overrides"` // differences from the defaults
+}
+
+type CapabilityMatrixResponse struct {
+	Body CapabilityMatrix
+}
+
+type CapabilityOverrideRequest struct {
+	Id   string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	Body struct {
+		Role       queries.PlaylistRole `json:"role" enum:"viewer,moderator" doc:"owner capabilities can not be changed"`
+		Capability Capability           `json:"capability" enum:"submit,approve,decline,unapprove,reorder,export,import,mute,manage_members,edit_settings"`
+		Allowed    *bool                `json:"allowed" required:"false" doc:"null - reset to default"`
+	}
+}
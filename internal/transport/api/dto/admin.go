@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+// действия в истории модерации
+const (
+	ActionApprove   = "approve"
+	ActionDecline   = "decline"
+	ActionUnapprove = "unapprove"
+	ActionMute      = "mute"
+	ActionUnmute    = "unmute"
+	ActionTransfer  = "transfer"
+	ActionOverride  = "override"
+	ActionDelete    = "admin_delete"
+	ActionRestore   = "admin_restore"
+	ActionReassign  = "admin_reassign"
+)
+
+type AdminPlaylist struct {
+	Id           string     `json:"id"`
+	Title        string     `json:"title"`
+	Thumbnail    string     `json:"thumbnail"`
+	Type         string     `json:"type"`
+	TelegramId   int64      `json:"telegram_id,omitempty" example:"-1002345678901"`
+	TopicId      int        `json:"topic_id,omitempty" example:"42"`
+	Count        int        `json:"count"`
+	AllowedCount int        `json:"allowed_count"`
+	OwnerId      int64      `json:"owner_id,omitempty" example:"687627953"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+}
+
+type HistoryEntry struct {
+	Id           int64     `json:"id"`
+	ActorId      int64     `json:"actor_id" example:"687627953"`
+	Action       string    `json:"action" example:"approve"`
+	TargetUserId int64     `json:"target_user_id,omitempty" example:"687627953"`
+	TrackId      string    `json:"track_id,omitempty" example:"dQw4w9WgXcQ"`
+	Details      string    `json:"details,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type AdminPlaylistsRequest struct {
+	Query   string `query:"q" maxLength:"256" doc:"title, playlist id or telegram chat id"`
+	Deleted bool   `query:"deleted" doc:"show deleted playlists instead of active"`
+	Limit   int32  `query:"limit" minimum:"1" maximum:"100" default:"50"`
+	Offset  int32  `query:"offset" minimum:"0" default:"0"`
+}
+
+type AdminPlaylistsResponse struct {
+	Body []AdminPlaylist
+}
+
+type AdminUsersRequest struct {
+	Query  string `query:"q" maxLength:"256" doc:"name, username or telegram id"`
+	Limit  int32  `query:"limit" minimum:"1" maximum:"100" default:"50"`
+	Offset int32  `query:"offset" minimum:"0" default:"0"`
+}
+
+type AdminUsersResponse struct {
+	Body []User
+}
+
+type AdminPlaylistRequest struct {
+	Id string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+}
+
+type HistoryRequest struct {
+	Id     string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	Limit  int32  `query:"limit" minimum:"1" maximum:"100" default:"50"`
+	Offset int32  `query:"offset" minimum:"0" default:"0"`
+}
+
+type HistoryResponse struct {
+	Body []HistoryEntry
+}
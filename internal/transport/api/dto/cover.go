@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// CoverMaxBytes - максимальный размер загружаемой обложки
+const CoverMaxBytes = 5 << 20
+
+type CoverForm struct {
+	Image huma.FormFile `form:"image" contentType:"image/jpeg,image/png,image/gif" required:"true" doc:"JPEG, PNG or GIF up to 5 MB"`
+}
+
+type CoverRequest struct {
+	Id      string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	RawBody huma.MultipartFormFiles[CoverForm]
+}
+
+type Cover struct {
+	Thumbnail string `json:"thumbnail" doc:"cover url, immutable and cacheable"`
+}
+
+type CoverResponse struct {
+	Body Cover
+}
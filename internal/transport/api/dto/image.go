@@ -0,0 +1,15 @@
+package dto
+
+// ImageWidths - ширины, до которых прокси уменьшает картинки, 0 - исходный размер
+var ImageWidths = []int{0, 64, 128, 256, 544}
+
+type ImageRequest struct {
+	Hash  string `path:"hash" pattern:"^[0-9a-f]{64}$" doc:"sha256 of the original image url"`
+	Width int    `query:"w" enum:"0,64,128,256,544" default:"0" doc:"resize to width, 0 - original size"`
+}
+
+type ImageResponse struct {
+	ContentType  string `header:"Content-Type"`
+	CacheControl string `header:"Cache-Control"`
+	Body         []byte
+}
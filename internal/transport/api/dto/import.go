@@ -0,0 +1,63 @@
+package dto
+
+const (
+	ImportMatched   = "matched"
+	ImportAmbiguous = "ambiguous"
+	ImportFailed    = "failed"
+)
+
+type ImportMapping struct {
+	Title     string `query:"title" default:"title" doc:"track title column"`
+	Artist    string `query:"artist" default:"artist" doc:"artist column"`
+	Link      string `query:"link" default:"link" doc:"youtube link column"`
+	Submitter string `query:"submitter" default:"submitter" doc:"submitter column, saved as the submitter name of new tracks"`
+}
+
+type ImportCSVRequest struct {
+	Id string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	ImportMapping
+	RawBody []byte `contentType:"text/csv"`
+}
+
+type ImportRow struct {
+	Row        int     `json:"row"` // номер строки без заголовка, с 1
+	Title      string  `json:"title,omitempty"`
+	Artist     string  `json:"artist,omitempty"`
+	Link       string  `json:"link,omitempty"`
+	Submitter  string  `json:"submitter,omitempty"`
+	Status     string  `json:"status" enum:"matched,ambiguous,failed"`
+	Track      *Track  `json:"track,omitempty"`
+	Candidates []Track `json:"candidates,omitempty"` // варианты для ambiguous
+	Error      string  `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	Matched   int         `json:"matched"`
+	Ambiguous int         `json:"ambiguous"`
+	Failed    int         `json:"failed"`
+	Rows      []ImportRow `json:"rows"`
+}
+
+type ImportResponse struct {
+	Body ImportReport
+}
+
+type ImportPlaylistRequest struct {
+	Id   string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	Body struct {
+		Url     string `json:"url" minLength:"1" example:"https://music.youtube.com/playlist?list=PLxxxxxxxxxxxxxxxx" doc:"youtube music playlist url or id"`
+		Pending bool   `json:"pending,omitempty" doc:"add tracks on moderation instead of approved"`
+	}
+}
+
+type PlaylistImport struct {
+	ExternalId string `json:"external_id"`
+	Title      string `json:"title"`
+	Found      int    `json:"found"`     // треков в исходном плейлисте
+	Added      int    `json:"added"`     // новых треков в нашем плейлисте
+	Truncated  bool   `json:"truncated"` // плейлист слишком длинный, импортированы не все треки
+}
+
+type PlaylistImportResponse struct {
+	Body PlaylistImport
+}
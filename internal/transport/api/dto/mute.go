@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type Mute struct {
+	User
+	MutedBy   int64      `json:"muted_by" example:"687627953"`
+	Reason    string     `json:"reason,omitempty" example:"spam"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // null - until unmuted
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type MutesResponse struct {
+	Body []Mute
+}
+
+type MuteRequest struct {
+	Id   string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	Body struct {
+		UserId   int64  `json:"user_id" example:"687627953"`
+		Duration int    `json:"duration,omitempty" minimum:"0" example:"3600" doc:"mute duration in seconds, 0 - until unmuted"`
+		Reason   string `json:"reason,omitempty" maxLength:"256" example:"spam"`
+	}
+}
+
+type UnmuteRequest struct {
+	Id     string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	UserId int64  `path:"user_id" example:"687627953"`
+}
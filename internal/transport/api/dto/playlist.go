@@ -15,6 +15,10 @@ type Playlist struct {
 	Length       int                  `json:"length"`
 	Role         queries.PlaylistRole `json:"role"`
 	Type         string               `json:"type"`
+	Language     string               `json:"language" doc:"bot language in the group"`
+	TopicId      int                  `json:"topic_id,omitempty" doc:"forum topic of the group playlist, 0 - the whole group"`
+	Members      []Member             `json:"members,omitempty"`
+	Capabilities []Capability         `json:"capabilities,omitempty"` // what the current user can do
 }
 
 type PlaylistByIdResponse struct {
@@ -24,3 +28,17 @@ type PlaylistByIdResponse struct {
 type PlaylistsResponse struct {
 	Body []Playlist
 }
+
+type TransferRequest struct {
+	Id   string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	Body struct {
+		UserId int64 `json:"user_id" example:"687627953" doc:"member who becomes the owner"`
+	}
+}
+
+type ExportRequest struct {
+	Id     string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	Format string `query:"format" enum:"m3u8,xspf,csv,json,rekordbox,traktor" default:"m3u8" doc:"export format"`
+	All    bool   `query:"all" doc:"include tracks on moderation"`
+	Path   string `query:"path" default:"/Music/{authors} - {title}.mp3" example:"C:/Music/{index} {authors} - {title}.mp3" doc:"local audio file path template for rekordbox and traktor: {index}, {id}, {title}, {authors}"`
+}
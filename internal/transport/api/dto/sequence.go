@@ -0,0 +1,34 @@
+package dto
+
+type SequenceOptions struct {
+	TargetLength    int     `json:"target_length,omitempty" minimum:"0" example:"3600" doc:"target total duration in seconds, 0 - no limit"`
+	NoRepeatArtist  bool    `json:"no_repeat_artist,omitempty" doc:"do not put tracks of the same artist back-to-back"`
+	ExcludeExplicit bool    `json:"exclude_explicit,omitempty" doc:"exclude explicit tracks"`
+	First           string  `json:"first,omitempty" maxLength:"11" example:"dQw4w9WgXcQ" doc:"track pinned to the start"`
+	Last            string  `json:"last,omitempty" maxLength:"11" example:"dQw4w9WgXcQ" doc:"track pinned to the end"`
+	Seed            *uint64 `json:"seed,omitempty" doc:"seed for shuffling, any value including 0 repeats the same order; random if not set"`
+}
+
+type Sequence struct {
+	Seed      uint64   `json:"seed"`
+	Tracks    []Track  `json:"tracks"`
+	Length    int      `json:"length"`
+	Trimmed   []string `json:"trimmed"`   // approved tracks left out of the sequence
+	Conflicts int      `json:"conflicts"` // same artist pairs, that could not be separated
+}
+
+type SequenceRequest struct {
+	Id   string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	Body SequenceOptions
+}
+
+type SequenceResponse struct {
+	Body Sequence
+}
+
+type SequenceAcceptRequest struct {
+	Id   string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+	Body struct {
+		TrackIds []string `json:"track_ids" minItems:"1" doc:"ordered track ids from the sequence"`
+	}
+}
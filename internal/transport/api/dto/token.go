@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+// права API токенов
+const (
+	ScopeRead     = "playlists:read"
+	ScopeSubmit   = "tracks:submit"
+	ScopeModerate = "tracks:moderate"
+	ScopeExport   = "playlists:export"
+)
+
+// TokenGrant - что разрешено API токену, которым подписан запрос
+type TokenGrant struct {
+	TokenId   string
+	Scopes    []string
+	Playlists []string // пустой - все плейлисты юзера
+}
+
+type ApiToken struct {
+	Id         string     `json:"id" example:"01JZ35PYGP6HJA08H0NHYPBHWD"`
+	Name       string     `json:"name" example:"event screen"`
+	Scopes     []string   `json:"scopes" example:"playlists:read"`
+	Playlists  []string   `json:"playlists"` // empty - all playlists of the user
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+type CreatedApiToken struct {
+	ApiToken
+	Token string `json:"token" example:"muse_hHvgK3H1xk4qDq7bLx0V1fE9u6nT0m2oZ8cWcYp4a9Q"` // Shown only once
+}
+
+type CreateApiTokenRequest struct {
+	Body struct {
+		Name      string   `json:"name" minLength:"1" maxLength:"64" example:"event screen"`
+		Scopes    []string `json:"scopes" minItems:"1" enum:"playlists:read,tracks:submit,tracks:moderate,playlists:export" doc:"allowed operations"`
+		Playlists []string `json:"playlists,omitempty" doc:"playlist ids the token is restricted to, all playlists if empty"`
+		ExpiresIn int      `json:"expires_in,omitempty" minimum:"0" example:"30" doc:"lifetime in days, 0 - no expiration"`
+	}
+}
+
+type CreateApiTokenResponse struct {
+	Body CreatedApiToken
+}
+
+type ApiTokensResponse struct {
+	Body []ApiToken
+}
+
+type RevokeApiTokenRequest struct {
+	Id string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"token id"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type Track struct {
 	Id        string `json:"id"`
 	Title     string `json:"title"`
@@ -7,6 +9,31 @@ type Track struct {
 	Thumbnail string `json:"thumbnail"`
 	Length    int32  `json:"length"`
 	Explicit  bool   `json:"explicit"`
+	Submitter string `json:"submitter,omitempty" doc:"display name of who suggested the track, only in playlist responses"`
+}
+
+// Submission - трек, предложенный в плейлист и ожидающий модерации
+type Submission struct {
+	PlaylistId    string
+	PlaylistTitle string
+	TelegramId    int64 // чат группы плейлиста, 0 - плейлист без группы
+	TopicId       int   // тема форума в группе, 0 - вся группа
+	Language      string
+	Track         Track
+	UserId        int64
+}
+
+// статусы предложенного трека
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusDeclined = "declined"
+)
+
+type SubmissionStatus struct {
+	Track
+	Status      string    `json:"status" enum:"pending,approved,declined"`
+	SubmittedAt time.Time `json:"submitted_at"`
 }
 
 type TrackAction struct {
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"backend/internal/infra/queries"
+	"strconv"
+	"strings"
+)
+
+type User struct {
+	Id           int64  `json:"id" example:"687627953"`
+	Name         string `json:"name" example:"Linuxfight Olukhovich"` // Display name: first and last name, username or id
+	FirstName    string `json:"first_name" example:"Linuxfight"`
+	LastName     string `json:"last_name,omitempty" example:"Olukhovich"`
+	Username     string `json:"username,omitempty" example:"warl0rdd"`
+	LanguageCode string `json:"language_code,omitempty" example:"ru"`
+	PhotoUrl     string `json:"photo_url,omitempty"`
+}
+
+type Member struct {
+	User
+	Role queries.PlaylistRole `json:"role"`
+}
+
+type MeResponse struct {
+	Body User
+}
+
+// DisplayName - имя для показа: имя и фамилия, если их нет - username, в крайнем случае ID
+func DisplayName(id int64, firstName, lastName, username string) string {
+	if name := strings.TrimSpace(firstName + " " + lastName); name != "" {
+		return name
+	}
+
+	if username != "" {
+		return "@" + username
+	}
+
+	return "id" + strconv.FormatInt(id, 10)
+}
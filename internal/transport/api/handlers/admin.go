@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"backend/internal/interfaces"
+	"backend/internal/service"
+	"backend/internal/transport/api/dto"
+	"backend/internal/transport/api/middlewares"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.uber.org/zap"
+)
+
+type Admin struct {
+	adminService interfaces.AdminService
+
+	logger *zap.Logger
+}
+
+// NewAdmin - создать новый экземпляр обработчика. Все маршруты в группе /api/admin только для администраторов
+func NewAdmin(adminService *service.Admin, logger *zap.Logger, api huma.API, authMiddleware *middlewares.Auth) *Admin {
+	result := &Admin{
+		adminService: adminService,
+		logger:       logger,
+	}
+
+	group := huma.NewGroup(api, "/api/admin")
+	group.UseMiddleware(authMiddleware.IsAdmin)
+
+	result.setup(group)
+
+	return result
+}
+
+// playlists - поиск плейлистов
+func (h *Admin) playlists(ctx context.Context, input *dto.AdminPlaylistsRequest) (*dto.AdminPlaylistsResponse, error) {
+	resp, err := h.adminService.Playlists(ctx, input.Query, input.Deleted, input.Limit, input.Offset)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("adminPlaylists error: query - %s", input.Query), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.AdminPlaylistsResponse{Body: resp}, nil
+}
+
+// users - поиск юзеров
+func (h *Admin) users(ctx context.Context, input *dto.AdminUsersRequest) (*dto.AdminUsersResponse, error) {
+	resp, err := h.adminService.Users(ctx, input.Query, input.Limit, input.Offset)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("adminUsers error: query - %s", input.Query), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.AdminUsersResponse{Body: resp}, nil
+}
+
+// delete - удалить плейлист (с возможностью восстановления)
+func (h *Admin) delete(ctx context.Context, input *dto.AdminPlaylistRequest) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("adminDelete: admin_id - %d, playlist_id - %s", val, input.Id))
+
+	if err := h.adminService.DeletePlaylist(ctx, input.Id, val); err != nil {
+		h.logger.Error(fmt.Sprintf("adminDelete error: admin_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
+
+// restore - восстановить удалённый плейлист
+func (h *Admin) restore(ctx context.Context, input *dto.AdminPlaylistRequest) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("adminRestore: admin_id - %d, playlist_id - %s", val, input.Id))
+
+	if err := h.adminService.RestorePlaylist(ctx, input.Id, val); err != nil {
+		h.logger.Error(fmt.Sprintf("adminRestore error: admin_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
+
+// reassign - назначить нового владельца
+func (h *Admin) reassign(ctx context.Context, input *dto.TransferRequest) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("adminReassign: admin_id - %d, playlist_id - %s, owner_id - %d", val, input.Id, input.Body.UserId))
+
+	if err := h.adminService.ReassignOwner(ctx, input.Id, input.Body.UserId, val); err != nil {
+		h.logger.Error(fmt.Sprintf("adminReassign error: admin_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
+
+// history - история модерации плейлиста
+func (h *Admin) history(ctx context.Context, input *dto.HistoryRequest) (*dto.HistoryResponse, error) {
+	resp, err := h.adminService.History(ctx, input.Id, input.Limit, input.Offset)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("adminHistory error: playlist_id - %s", input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.HistoryResponse{Body: resp}, nil
+}
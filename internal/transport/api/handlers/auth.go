@@ -1,15 +1,17 @@
 package handlers
 
 import (
+	"backend/internal/infra"
 	"backend/internal/interfaces"
 	"backend/internal/service"
 	"backend/internal/transport/api/dto"
+	"backend/internal/transport/api/middlewares"
 	"backend/pkg/utils"
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/danielgtaylor/huma/v2"
-	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
@@ -21,51 +23,161 @@ type Auth struct {
 }
 
 // NewAuth - создать новый экземпляр обработчика
-func NewAuth(userService *service.User, authService *service.Auth, logger *zap.Logger, api huma.API) *Auth {
+func NewAuth(cfg *infra.Config, userService *service.User, authService *service.Auth, logger *zap.Logger, api huma.API, authMiddleware *middlewares.Auth) *Auth {
 	result := &Auth{
 		userService: userService,
 		authService: authService,
 		logger:      logger,
 	}
 
-	result.setup(api)
+	result.setup(api, authMiddleware.IsAuthenticated)
+
+	if cfg.Debug {
+		logger.Warn("debug mode: /api/auth/dev mints init data for any user")
+		result.setupDev(api)
+	}
 
 	return result
 }
 
-// login - Получить токен для взаимодействия. Нуждается в Raw строке из Telegram Mini App. Действует 1 час
+// login - Получить токены для взаимодействия. Нуждается в Raw строке из Telegram Mini App
 func (h *Auth) login(ctx context.Context, input *dto.AuthInputStruct) (*dto.AuthOutputStruct, error) {
-	id, err := h.authService.ParseInitData(input.Body.Raw)
+	profile, err := h.authService.ParseInitData(input.Body.Raw)
 	if err != nil {
 		h.logger.Warn("login error", zap.Error(err))
 
 		return nil, utils.Convert(err)
 	}
 
-	if err := h.userService.GetByID(ctx, id); err != nil {
-		if !errors.Is(err, pgx.ErrNoRows) {
-			h.logger.Error("login error", zap.Error(err))
+	// профиль обновляется при каждом входе
+	if err := h.userService.Upsert(ctx, profile); err != nil {
+		h.logger.Error("login error", zap.Error(err))
 
-			return nil, utils.Convert(err)
-		}
+		return nil, utils.Convert(err)
+	}
 
-		if err := h.userService.Create(ctx, id); err != nil {
-			h.logger.Error("login error", zap.Error(err))
+	tokenData, err := h.authService.CreateSession(ctx, profile.ID)
+	if err != nil {
+		h.logger.Error("login error", zap.Error(err))
 
-			return nil, utils.Convert(err)
-		}
+		return nil, utils.Convert(err)
 	}
 
-	token, err := h.authService.GenerateToken(id)
+	return &dto.AuthOutputStruct{Body: tokenData}, nil
+}
+
+// loginWidget - получить токены через Telegram Login Widget, для входа из браузера
+func (h *Auth) loginWidget(ctx context.Context, input *dto.LoginWidgetInputStruct) (*dto.AuthOutputStruct, error) {
+	profile, err := h.authService.ParseLoginWidget(input.Body)
 	if err != nil {
-		h.logger.Error("login error", zap.Error(err))
+		h.logger.Warn("login widget error", zap.Error(err))
 
 		return nil, utils.Convert(err)
 	}
 
-	tokenData := dto.Token{
-		Token: token,
+	if err := h.userService.Upsert(ctx, profile); err != nil {
+		h.logger.Error("login widget error", zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	tokenData, err := h.authService.CreateSession(ctx, profile.ID)
+	if err != nil {
+		h.logger.Error("login widget error", zap.Error(err))
+
+		return nil, utils.Convert(err)
 	}
 
 	return &dto.AuthOutputStruct{Body: tokenData}, nil
 }
+
+// devInitData - подписать init data для произвольного юзера. Только в режиме отладки
+func (h *Auth) devInitData(_ context.Context, input *dto.DevInitDataInputStruct) (*dto.DevInitDataOutputStruct, error) {
+	raw, err := h.authService.MintInitData(dto.TelegramData{
+		ID:           input.Body.ID,
+		FirstName:    input.Body.FirstName,
+		LastName:     input.Body.LastName,
+		Username:     input.Body.Username,
+		LanguageCode: input.Body.LanguageCode,
+		PhotoUrl:     input.Body.PhotoUrl,
+	})
+	if err != nil {
+		h.logger.Error("dev init data error", zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.DevInitDataOutputStruct{Body: dto.InitData{Raw: raw}}, nil
+}
+
+// refresh - обменять refresh токен на новую пару токенов
+func (h *Auth) refresh(ctx context.Context, input *dto.RefreshInputStruct) (*dto.AuthOutputStruct, error) {
+	tokenData, err := h.authService.Refresh(ctx, input.Body.RefreshToken)
+	if err != nil {
+		h.logger.Warn("refresh error", zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.AuthOutputStruct{Body: tokenData}, nil
+}
+
+// logout - отозвать текущую сессию или все сессии юзера
+func (h *Auth) logout(ctx context.Context, input *dto.LogoutInputStruct) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	sessionID, ok := ctx.Value(middlewares.SessionKey).(string)
+	if !ok {
+		h.logger.Error("session not found in context")
+
+		return nil, utils.Convert(errors.New("session not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("logout: user_id - %d, session_id - %s, all - %t", val, sessionID, input.All))
+
+	var err error
+	if input.All {
+		err = h.authService.RevokeAll(ctx, val)
+	} else {
+		err = h.authService.Revoke(ctx, sessionID)
+	}
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("logout error: user_id - %d, session_id - %s", val, sessionID), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
+
+// jwks - публичные ключи для проверки JWT
+func (h *Auth) jwks(_ context.Context, _ *struct{}) (*dto.JWKSOutputStruct, error) {
+	return &dto.JWKSOutputStruct{
+		CacheControl: "public, max-age=3600",
+		Body:         h.authService.JWKS(),
+	}, nil
+}
+
+// me - профиль текущего юзера
+func (h *Auth) me(ctx context.Context, _ *struct{}) (*dto.MeResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	user, err := h.userService.Get(ctx, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("me error: user_id - %d", val), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.MeResponse{Body: user}, nil
+}
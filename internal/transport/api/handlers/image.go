@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"backend/internal/interfaces"
+	"backend/internal/service"
+	"backend/internal/transport/api/dto"
+	"backend/pkg/storage"
+	"backend/pkg/utils"
+	"context"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.uber.org/zap"
+)
+
+type Image struct {
+	imageService interfaces.ImageService
+
+	logger *zap.Logger
+}
+
+// NewImage - создать новый экземпляр обработчика
+func NewImage(imageService *service.Images, logger *zap.Logger, api huma.API) *Image {
+	result := &Image{
+		imageService: imageService,
+		logger:       logger,
+	}
+
+	result.setup(api)
+
+	return result
+}
+
+// get - обложка трека через прокси. Без авторизации: картинки грузятся тегом img
+func (h *Image) get(ctx context.Context, input *dto.ImageRequest) (*dto.ImageResponse, error) {
+	data, err := h.imageService.Get(ctx, input.Hash, input.Width)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("image error: hash - %s, width - %d", input.Hash, input.Width), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.ImageResponse{
+		ContentType:  "image/jpeg",
+		CacheControl: storage.CacheControl,
+		Body:         data,
+	}, nil
+}
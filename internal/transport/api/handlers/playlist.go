@@ -5,10 +5,14 @@ import (
 	"backend/internal/service"
 	"backend/internal/transport/api/dto"
 	"backend/internal/transport/api/middlewares"
+	"backend/pkg/export"
 	"backend/pkg/utils"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"go.uber.org/zap"
@@ -17,19 +21,23 @@ import (
 type Playlist struct {
 	playlistService   interfaces.PlaylistService
 	permissionService interfaces.PermissionService
+	accessService     interfaces.AccessService
+	moderationService interfaces.ModerationService
 
 	logger *zap.Logger
 }
 
 // NewPlaylist - создать новый экземпляр обработчика
-func NewPlaylist(playlistService *service.Playlist, permissionService *service.Permission, logger *zap.Logger, api huma.API, authMiddleware *middlewares.Auth) *Playlist {
+func NewPlaylist(playlistService *service.Playlist, permissionService *service.Permission, accessService *service.Access, moderationService *service.Moderation, logger *zap.Logger, api huma.API, authMiddleware *middlewares.Auth) *Playlist {
 	result := &Playlist{
 		playlistService:   playlistService,
 		permissionService: permissionService,
+		accessService:     accessService,
+		moderationService: moderationService,
 		logger:            logger,
 	}
 
-	result.setup(api, authMiddleware.IsAuthenticated)
+	result.setup(api, authMiddleware)
 
 	return result
 }
@@ -75,5 +83,269 @@ func (h *Playlist) getAll(ctx context.Context, _ *struct{}) (*dto.PlaylistsRespo
 		return nil, utils.Convert(err)
 	}
 
+	// API токен может быть ограничен частью плейлистов
+	resp = slices.DeleteFunc(resp, func(playlist dto.Playlist) bool {
+		return !middlewares.AllowedPlaylist(ctx, playlist.Id)
+	})
+
 	return &dto.PlaylistsResponse{Body: resp}, nil
 }
+
+// sequence - составить порядок проигрывания разрешённых треков, без сохранения
+func (h *Playlist) sequence(ctx context.Context, input *dto.SequenceRequest) (*dto.SequenceResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("sequence: user_id - %d, playlist_id - %s", val, input.Id))
+
+	resp, err := h.playlistService.Sequence(ctx, input.Id, input.Body, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("sequence error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.SequenceResponse{Body: resp}, nil
+}
+
+// acceptSequence - сохранить принятый порядок треков
+func (h *Playlist) acceptSequence(ctx context.Context, input *dto.SequenceAcceptRequest) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("acceptSequence: user_id - %d, playlist_id - %s", val, input.Id))
+
+	if err := h.playlistService.Reorder(ctx, input.Id, input.Body.TrackIds, val); err != nil {
+		h.logger.Error(fmt.Sprintf("acceptSequence error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
+
+// export - выгрузить треки плейлиста файлом
+func (h *Playlist) export(ctx context.Context, input *dto.ExportRequest) (*huma.StreamResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("export: user_id - %d, playlist_id - %s, format - %s", val, input.Id, input.Format))
+
+	format, ok := export.Get(input.Format)
+	if !ok {
+		return nil, huma.Error422UnprocessableEntity("unknown format " + input.Format)
+	}
+
+	playlist, err := h.playlistService.Export(ctx, input.Id, input.All, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("export error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &huma.StreamResponse{
+		Body: func(hctx huma.Context) {
+			hctx.SetHeader("Content-Type", format.ContentType)
+			hctx.SetHeader("Content-Disposition", format.Disposition(playlist.Title))
+
+			if err := format.Write(hctx.BodyWriter(), playlist, export.Options{PathTemplate: input.Path}); err != nil {
+				h.logger.Error(fmt.Sprintf("export write error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+			}
+		},
+	}, nil
+}
+
+// capabilities - права ролей в плейлисте
+func (h *Playlist) capabilities(ctx context.Context, input *struct {
+	Id string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+}) (*dto.CapabilityMatrixResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	resp, err := h.accessService.Matrix(ctx, input.Id, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("capabilities error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.CapabilityMatrixResponse{Body: resp}, nil
+}
+
+// overrideCapability - разрешить или запретить роли действие в плейлисте
+func (h *Playlist) overrideCapability(ctx context.Context, input *dto.CapabilityOverrideRequest) (*dto.CapabilityMatrixResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("overrideCapability: user_id - %d, playlist_id - %s, role - %s, capability - %s", val, input.Id, input.Body.Role, input.Body.Capability))
+
+	if err := h.accessService.SetOverride(ctx, input.Id, input.Body.Role, input.Body.Capability, input.Body.Allowed, val); err != nil {
+		h.logger.Error(fmt.Sprintf("overrideCapability error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	resp, err := h.accessService.Matrix(ctx, input.Id, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("overrideCapability error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.CapabilityMatrixResponse{Body: resp}, nil
+}
+
+// mutes - заглушённые участники плейлиста
+func (h *Playlist) mutes(ctx context.Context, input *struct {
+	Id string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+}) (*dto.MutesResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	resp, err := h.moderationService.Mutes(ctx, input.Id, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("mutes error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.MutesResponse{Body: resp}, nil
+}
+
+// mute - запретить участнику предлагать треки
+func (h *Playlist) mute(ctx context.Context, input *dto.MuteRequest) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("mute: user_id - %d, playlist_id - %s, target_id - %d, duration - %d", val, input.Id, input.Body.UserId, input.Body.Duration))
+
+	if err := h.moderationService.Mute(ctx, input.Id, input.Body.UserId, time.Duration(input.Body.Duration)*time.Second, input.Body.Reason, val); err != nil {
+		h.logger.Error(fmt.Sprintf("mute error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
+
+// unmute - снять запрет
+func (h *Playlist) unmute(ctx context.Context, input *dto.UnmuteRequest) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("unmute: user_id - %d, playlist_id - %s, target_id - %d", val, input.Id, input.UserId))
+
+	if err := h.moderationService.Unmute(ctx, input.Id, input.UserId, val); err != nil {
+		h.logger.Error(fmt.Sprintf("unmute error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
+
+// transfer - передать владение плейлистом другому участнику
+func (h *Playlist) transfer(ctx context.Context, input *dto.TransferRequest) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("transfer: user_id - %d, playlist_id - %s, target_id - %d", val, input.Id, input.Body.UserId))
+
+	if err := h.permissionService.Transfer(ctx, input.Id, input.Body.UserId, val); err != nil {
+		h.logger.Error(fmt.Sprintf("transfer error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
+
+// leave - выйти из плейлиста
+func (h *Playlist) leave(ctx context.Context, input *struct {
+	Id string `path:"id" minLength:"26" maxLength:"26" example:"01JZ35PYGP6HJA08H0NHYPBHWD" doc:"playlist id"`
+}) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("leave: user_id - %d, playlist_id - %s", val, input.Id))
+
+	if err := h.permissionService.Leave(ctx, input.Id, val); err != nil {
+		h.logger.Error(fmt.Sprintf("leave error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
+
+// uploadCover - загрузить обложку плейлиста
+func (h *Playlist) uploadCover(ctx context.Context, input *dto.CoverRequest) (*dto.CoverResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	file := input.RawBody.Data().Image
+
+	h.logger.Info(fmt.Sprintf("uploadCover: user_id - %d, playlist_id - %s, size - %d", val, input.Id, file.Size))
+
+	if file.Size > dto.CoverMaxBytes {
+		return nil, huma.NewError(413, fmt.Sprintf("cover must be at most %d MB", dto.CoverMaxBytes>>20))
+	}
+
+	image, err := io.ReadAll(io.LimitReader(file, dto.CoverMaxBytes+1))
+	if err != nil {
+		return nil, utils.Convert(err)
+	}
+
+	thumbnail, err := h.playlistService.UploadCover(ctx, input.Id, image, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("uploadCover error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.CoverResponse{Body: dto.Cover{Thumbnail: thumbnail}}, nil
+}
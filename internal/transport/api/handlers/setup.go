@@ -1,13 +1,15 @@
 package handlers
 
 import (
+	"backend/internal/transport/api/dto"
+	"backend/internal/transport/api/middlewares"
 	"net/http"
 
 	"github.com/danielgtaylor/huma/v2"
 )
 
-// setup - добавить маршрут до эндпоинта
-func (h *Auth) setup(router huma.API) {
+// setup - добавить маршрут до эндпоинтов
+func (h *Auth) setup(router huma.API, auth func(ctx huma.Context, next func(ctx huma.Context))) {
 	huma.Register(router, huma.Operation{
 		OperationID: "auth",
 		Path:        "/api/auth",
@@ -21,18 +23,126 @@ func (h *Auth) setup(router huma.API) {
 			"auth",
 		},
 		Summary:     "Login",
-		Description: "Получить токен для взаимодействия. Нуждается в Raw строке из Telegram Mini App. Действует 1 час",
+		Description: "Получить токен для взаимодействия и refresh токен. Нуждается в Raw строке из Telegram Mini App. Токен короткоживущий (expires_in), для продления - refresh",
 	}, h.login)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "auth-widget",
+		Path:        "/api/auth/widget",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			422,
+			500,
+		},
+		Tags: []string{
+			"auth",
+		},
+		Summary:     "Login Widget",
+		Description: "Получить токены через Telegram Login Widget (вход из браузера). Нуждается в объекте юзера из callback виджета без изменений",
+	}, h.loginWidget)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "auth-refresh",
+		Path:        "/api/auth/refresh",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			422,
+			500,
+		},
+		Tags: []string{
+			"auth",
+		},
+		Summary:     "Refresh",
+		Description: "Обменять refresh токен на новую пару токенов. Refresh токен одноразовый, повторное использование отзывает сессию",
+	}, h.refresh)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "auth-logout",
+		Path:        "/api/auth/logout",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			500,
+		},
+		Tags: []string{
+			"auth",
+		},
+		Summary:     "Logout",
+		Description: "Отозвать текущую сессию. all=true - отозвать все сессии юзера",
+		Middlewares: huma.Middlewares{auth},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.logout)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "me",
+		Path:        "/api/me",
+		Method:      http.MethodGet,
+		Errors: []int{
+			401,
+			404,
+			500,
+		},
+		Tags: []string{
+			"auth",
+		},
+		Summary:     "Me",
+		Description: "Профиль текущего юзера из Telegram, обновляется при каждом входе",
+		Middlewares: huma.Middlewares{auth},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.me)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "auth-jwks",
+		Path:        "/.well-known/jwks.json",
+		Method:      http.MethodGet,
+		Errors: []int{
+			500,
+		},
+		Tags: []string{
+			"auth",
+		},
+		Summary:     "JWKS",
+		Description: "Публичные ключи для проверки токенов другими сервисами (по kid из заголовка токена). При подписи HS256 список пустой",
+	}, h.jwks)
+}
+
+// setupDev - маршруты для локальной разработки, регистрируются только при DEBUG=true
+func (h *Auth) setupDev(router huma.API) {
+	huma.Register(router, huma.Operation{
+		OperationID: "auth-dev",
+		Path:        "/api/auth/dev",
+		Method:      http.MethodPost,
+		Errors: []int{
+			422,
+			500,
+		},
+		Tags: []string{
+			"auth",
+		},
+		Summary:     "Dev init data",
+		Description: "Только при DEBUG=true. Подписать init data токеном бота для любого юзера, raw из ответа передаётся в /api/auth. Заменяет initdata.py",
+	}, h.devInitData)
 }
 
 // setup - добавить маршрут до эндпоинтов
-func (h *Playlist) setup(router huma.API, auth func(ctx huma.Context, next func(ctx huma.Context))) {
+func (h *Playlist) setup(router huma.API, auth *middlewares.Auth) {
 	huma.Register(router, huma.Operation{
 		OperationID: "playlist-by-id",
 		Path:        "/api/playlists/{id}",
 		Method:      http.MethodGet,
 		Errors: []int{
 			401,
+			403,
 			404,
 			422,
 			500,
@@ -42,7 +152,7 @@ func (h *Playlist) setup(router huma.API, auth func(ctx huma.Context, next func(
 		},
 		Summary:     "Get by ID",
 		Description: "Получить плейлист по ID. Для получения требуется, чтобы у юзера были права на просмотр плейлиста. При получении вернёт массив треков",
-		Middlewares: huma.Middlewares{auth},
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeRead)},
 		Security: []map[string][]string{
 			{
 				"jwt": []string{},
@@ -56,6 +166,7 @@ func (h *Playlist) setup(router huma.API, auth func(ctx huma.Context, next func(
 		Method:      http.MethodGet,
 		Errors: []int{
 			401,
+			403,
 			500,
 		},
 		Tags: []string{
@@ -63,16 +174,280 @@ func (h *Playlist) setup(router huma.API, auth func(ctx huma.Context, next func(
 		},
 		Summary:     "All",
 		Description: "Получить весь список плейлистов. Вернёт только те плейлисты, к которым у пользователя есть доступ. При получении не вернёт массив треков",
-		Middlewares: huma.Middlewares{auth},
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeRead)},
 		Security: []map[string][]string{
 			{
 				"jwt": []string{},
 			},
 		},
 	}, h.getAll)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-sequence",
+		Path:        "/api/playlists/{id}/sequence",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Sequence",
+		Description: "Составить порядок проигрывания из разрешённых треков: ограничение по длительности, без одного исполнителя подряд, без explicit, закреплённые первый/последний треки. Одинаковый seed даёт одинаковый результат. Ничего не сохраняет. У юзера должны быть права админа",
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.sequence)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-sequence-accept",
+		Path:        "/api/playlists/{id}/sequence/accept",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Accept sequence",
+		Description: "Сохранить порядок треков из sequence. Переданные треки встают в начало плейлиста в указанном порядке, остальные - после них. У юзера должны быть права админа",
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.acceptSequence)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-export",
+		Path:        "/api/playlists/{id}/export",
+		Method:      http.MethodGet,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Export",
+		Description: "Выгрузить плейлист файлом: m3u8, xspf, csv, json, а также rekordbox xml и traktor nml для DJ софта (путь до аудиофайлов задаётся шаблоном path). По умолчанию только разрешённые треки, all=true - вместе с треками на модерации",
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeExport)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.export)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-capabilities",
+		Path:        "/api/playlists/{id}/capabilities",
+		Method:      http.MethodGet,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Capabilities",
+		Description: "Права ролей в плейлисте (с учётом переопределений) и сами переопределения",
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeRead)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.capabilities)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-capabilities-override",
+		Path:        "/api/playlists/{id}/capabilities",
+		Method:      http.MethodPut,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Override capability",
+		Description: "Разрешить или запретить роли действие в этом плейлисте (например, зрителям - предлагать треки). allowed=null возвращает значение по умолчанию. Права владельца не меняются. Нужно право edit_settings",
+		Middlewares: huma.Middlewares{auth.IsAuthenticated},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.overrideCapability)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-mutes",
+		Path:        "/api/playlists/{id}/mutes",
+		Method:      http.MethodGet,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Mutes",
+		Description: "Участники, которым запрещено предлагать треки. Нужно право mute",
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.mutes)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-mute",
+		Path:        "/api/playlists/{id}/mutes",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Mute",
+		Description: "Запретить участнику предлагать треки, не удаляя его из группы. duration - на сколько секунд, 0 - до снятия. Заглушить можно только участника с ролью ниже своей. Нужно право mute",
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.mute)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-unmute",
+		Path:        "/api/playlists/{id}/mutes/{user_id}",
+		Method:      http.MethodDelete,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Unmute",
+		Description: "Снять запрет предлагать треки. Нужно право mute",
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.unmute)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-transfer",
+		Path:        "/api/playlists/{id}/transfer",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Transfer",
+		Description: "Передать владение плейлистом другому участнику, бывший владелец становится модератором. Нужно право manage_members (по умолчанию только у владельца). У плейлиста всегда ровно один владелец",
+		Middlewares: huma.Middlewares{auth.IsAuthenticated},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.transfer)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-leave",
+		Path:        "/api/playlists/{id}/leave",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Leave",
+		Description: "Выйти из плейлиста, он пропадёт из списка. Владелец сначала должен передать владение",
+		Middlewares: huma.Middlewares{auth.IsAuthenticated},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.leave)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "playlist-cover",
+		Path:        "/api/playlists/{id}/cover",
+		Method:      http.MethodPut,
+		Errors: []int{
+			401,
+			403,
+			404,
+			413,
+			422,
+			500,
+		},
+		Tags: []string{
+			"playlist",
+		},
+		Summary:     "Upload cover",
+		Description: "Загрузить обложку плейлиста (поле image в multipart/form-data): JPEG, PNG или GIF до 5 МБ. Картинка обрезается до квадрата и перекодируется в JPEG 640 и 160 пикселей, thumbnail указывает на 640. Нужно право edit_settings",
+		Middlewares: huma.Middlewares{auth.IsAuthenticated, middlewares.BodyLimit(dto.CoverMaxBytes + 64<<10)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.uploadCover)
 }
 
-func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx huma.Context))) {
+func (h *Track) setup(router huma.API, auth *middlewares.Auth) {
 	huma.Register(router, huma.Operation{
 		OperationID: "track-search",
 		Path:        "/api/search",
@@ -80,6 +455,7 @@ func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx
 		Errors: []int{
 			400,
 			401,
+			403,
 			422,
 			500,
 		},
@@ -88,7 +464,7 @@ func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx
 		},
 		Summary:     "Search",
 		Description: "Найти трек по запросу. Поиск по Youtube Music",
-		Middlewares: huma.Middlewares{auth},
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeRead)},
 		Security: []map[string][]string{
 			{
 				"jwt": []string{},
@@ -102,6 +478,7 @@ func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx
 		Method:      http.MethodPost,
 		Errors: []int{
 			401,
+			403,
 			404,
 			422,
 			500,
@@ -111,7 +488,7 @@ func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx
 		},
 		Summary:     "Submit",
 		Description: "Добавить трек в плейлист, если юзер есть в плейлисте. Если у юзера права админа, то трек добавляется в разрешённые, иначе на модерацию",
-		Middlewares: huma.Middlewares{auth},
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeSubmit)},
 		Security: []map[string][]string{
 			{
 				"jwt": []string{},
@@ -125,6 +502,7 @@ func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx
 		Method:      http.MethodDelete,
 		Errors: []int{
 			401,
+			403,
 			404,
 			422,
 			500,
@@ -134,7 +512,7 @@ func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx
 		},
 		Summary:     "Unapprove",
 		Description: "Убрать трек из разрешённых. У юзера должны быть права админа",
-		Middlewares: huma.Middlewares{auth},
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
 		Security: []map[string][]string{
 			{
 				"jwt": []string{},
@@ -148,6 +526,7 @@ func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx
 		Method:      http.MethodPatch,
 		Errors: []int{
 			401,
+			403,
 			404,
 			500,
 		},
@@ -156,7 +535,7 @@ func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx
 		},
 		Summary:     "Approve",
 		Description: "Добавить трек в разрешённые. У юзера должны быть права админа",
-		Middlewares: huma.Middlewares{auth},
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
 		Security: []map[string][]string{
 			{
 				"jwt": []string{},
@@ -180,11 +559,288 @@ func (h *Track) setup(router huma.API, auth func(ctx huma.Context, next func(ctx
 		},
 		Summary:     "Decline",
 		Description: "Удалить трек из кандидатов в плейлист. У юзера должны быть права админа",
-		Middlewares: huma.Middlewares{auth},
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
 		Security: []map[string][]string{
 			{
 				"jwt": []string{},
 			},
 		},
 	}, h.decline)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "tracks-import-csv",
+		Path:        "/api/playlists/{id}/import",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"tracks",
+		},
+		Summary:      "Import CSV",
+		Description:  "Импортировать треки из CSV (например, выгрузки ответов Google Forms). Названия колонок задаются параметрами title, artist, link, submitter. Строки со ссылкой на Youtube ищутся по ссылке, остальные - через поиск. Найденные треки добавляются на модерацию, в ответе - отчёт по каждой строке. У юзера должны быть права админа",
+		MaxBodyBytes: 1 << 20,
+		Middlewares:  huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.importCSV)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "tracks-import-youtube",
+		Path:        "/api/playlists/{id}/import/youtube",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"tracks",
+		},
+		Summary:     "Import Youtube Music playlist",
+		Description: "Импортировать все треки плейлиста Youtube Music по ссылке. По умолчанию треки добавляются в разрешённые, pending=true - на модерацию. ID исходного плейлиста сохраняется в плейлисте. Очень длинные плейлисты импортируются не полностью, тогда в ответе truncated=true. У юзера должны быть права админа",
+		Middlewares: huma.Middlewares{auth.Scoped(dto.ScopeModerate)},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.importPlaylist)
+}
+
+// setup - добавить маршрут до эндпоинтов. API токенами управлять можно только из сессии
+func (h *Token) setup(router huma.API, auth func(ctx huma.Context, next func(ctx huma.Context))) {
+	huma.Register(router, huma.Operation{
+		OperationID: "tokens-create",
+		Path:        "/api/tokens",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"tokens",
+		},
+		Summary:     "Create",
+		Description: "Выпустить API токен для интеграций с правами (scopes) и, опционально, ограничением по плейлистам. Токен показывается только один раз, передаётся как Bearer вместо JWT",
+		Middlewares: huma.Middlewares{auth},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.create)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "tokens-list",
+		Path:        "/api/tokens",
+		Method:      http.MethodGet,
+		Errors: []int{
+			401,
+			500,
+		},
+		Tags: []string{
+			"tokens",
+		},
+		Summary:     "List",
+		Description: "Действующие API токены юзера, без самих токенов",
+		Middlewares: huma.Middlewares{auth},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.list)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "tokens-revoke",
+		Path:        "/api/tokens/{id}",
+		Method:      http.MethodDelete,
+		Errors: []int{
+			401,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"tokens",
+		},
+		Summary:     "Revoke",
+		Description: "Отозвать API токен",
+		Middlewares: huma.Middlewares{auth},
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.revoke)
+}
+
+// setup - добавить маршруты админки. router - группа /api/admin с проверкой прав администратора
+func (h *Admin) setup(router huma.API) {
+	huma.Register(router, huma.Operation{
+		OperationID: "admin-playlists",
+		Path:        "/playlists",
+		Method:      http.MethodGet,
+		Errors: []int{
+			401,
+			403,
+			422,
+			500,
+		},
+		Tags: []string{
+			"admin",
+		},
+		Summary:     "Playlists",
+		Description: "Поиск плейлистов по названию, ID или ID чата Telegram. deleted=true - только удалённые",
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.playlists)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "admin-users",
+		Path:        "/users",
+		Method:      http.MethodGet,
+		Errors: []int{
+			401,
+			403,
+			422,
+			500,
+		},
+		Tags: []string{
+			"admin",
+		},
+		Summary:     "Users",
+		Description: "Поиск юзеров по имени, username или Telegram ID",
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.users)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "admin-playlist-delete",
+		Path:        "/playlists/{id}",
+		Method:      http.MethodDelete,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"admin",
+		},
+		Summary:     "Delete playlist",
+		Description: "Удалить плейлист у всех участников. Данные сохраняются, плейлист можно восстановить",
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.delete)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "admin-playlist-restore",
+		Path:        "/playlists/{id}/restore",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"admin",
+		},
+		Summary:     "Restore playlist",
+		Description: "Восстановить удалённый плейлист",
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.restore)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "admin-playlist-owner",
+		Path:        "/playlists/{id}/owner",
+		Method:      http.MethodPost,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"admin",
+		},
+		Summary:     "Reassign owner",
+		Description: "Назначить владельца плейлиста. Прежний владелец становится модератором, новый добавляется в участники, если его там не было",
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.reassign)
+
+	huma.Register(router, huma.Operation{
+		OperationID: "admin-playlist-history",
+		Path:        "/playlists/{id}/history",
+		Method:      http.MethodGet,
+		Errors: []int{
+			401,
+			403,
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"admin",
+		},
+		Summary:     "History",
+		Description: "История модерации плейлиста: решения по трекам, заглушения, передача владения, изменения прав и действия администраторов",
+		Security: []map[string][]string{
+			{
+				"jwt": []string{},
+			},
+		},
+	}, h.history)
+}
+
+// setup - добавить маршрут до эндпоинтов
+func (h *Image) setup(router huma.API) {
+	huma.Register(router, huma.Operation{
+		OperationID: "image-get",
+		Path:        "/api/images/{hash}",
+		Method:      http.MethodGet,
+		Errors: []int{
+			404,
+			422,
+			500,
+		},
+		Tags: []string{
+			"image",
+		},
+		Summary:     "Image",
+		Description: "Обложка трека через прокси, ссылки на него возвращаются в thumbnail треков. Картинка скачивается один раз и хранится в кеше, w - уменьшить до ширины",
+	}, h.get)
 }
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"backend/internal/interfaces"
+	"backend/internal/service"
+	"backend/internal/transport/api/dto"
+	"backend/internal/transport/api/middlewares"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.uber.org/zap"
+)
+
+type Token struct {
+	tokenService interfaces.TokenService
+
+	logger *zap.Logger
+}
+
+// NewToken - создать новый экземпляр обработчика
+func NewToken(tokenService *service.Token, logger *zap.Logger, api huma.API, authMiddleware *middlewares.Auth) *Token {
+	result := &Token{
+		tokenService: tokenService,
+		logger:       logger,
+	}
+
+	result.setup(api, authMiddleware.IsAuthenticated)
+
+	return result
+}
+
+// create - выпустить API токен
+func (h *Token) create(ctx context.Context, input *dto.CreateApiTokenRequest) (*dto.CreateApiTokenResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("createToken: user_id - %d, name - %s, scopes - %v", val, input.Body.Name, input.Body.Scopes))
+
+	resp, err := h.tokenService.Create(ctx, input.Body.Name, input.Body.Scopes, input.Body.Playlists, input.Body.ExpiresIn, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("createToken error: user_id - %d", val), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.CreateApiTokenResponse{Body: resp}, nil
+}
+
+// list - действующие API токены юзера
+func (h *Token) list(ctx context.Context, _ *struct{}) (*dto.ApiTokensResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	resp, err := h.tokenService.List(ctx, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("tokens error: user_id - %d", val), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.ApiTokensResponse{Body: resp}, nil
+}
+
+// revoke - отозвать API токен
+func (h *Token) revoke(ctx context.Context, input *dto.RevokeApiTokenRequest) (*struct{}, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("revokeToken: user_id - %d, token_id - %s", val, input.Id))
+
+	if err := h.tokenService.Revoke(ctx, input.Id, val); err != nil {
+		h.logger.Error(fmt.Sprintf("revokeToken error: user_id - %d, token_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return nil, nil
+}
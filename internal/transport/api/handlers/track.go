@@ -28,7 +28,7 @@ func NewTrack(playlistService *service.Playlist, trackService *service.Track, lo
 		logger:          logger,
 	}
 
-	result.setup(api, authMiddleware.IsAuthenticated)
+	result.setup(api, authMiddleware)
 
 	return result
 }
@@ -137,3 +137,45 @@ func (h *Track) approve(ctx context.Context, input *dto.TrackAction) (*struct{},
 
 	return nil, nil
 }
+
+// importCSV - импортировать треки из CSV на модерацию
+func (h *Track) importCSV(ctx context.Context, input *dto.ImportCSVRequest) (*dto.ImportResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("importCSV: user_id - %d, playlist_id - %s, size - %d", val, input.Id, len(input.RawBody)))
+
+	report, err := h.trackService.ImportCSV(ctx, input.Id, input.RawBody, input.ImportMapping, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("importCSV error: user_id - %d, playlist_id - %s", val, input.Id), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.ImportResponse{Body: report}, nil
+}
+
+// importPlaylist - импортировать плейлист Youtube Music по ссылке
+func (h *Track) importPlaylist(ctx context.Context, input *dto.ImportPlaylistRequest) (*dto.PlaylistImportResponse, error) {
+	val, ok := ctx.Value(middlewares.UserJwtKey).(int64)
+	if !ok {
+		h.logger.Error("user not found in context")
+
+		return nil, utils.Convert(errors.New("token not found in context"))
+	}
+
+	h.logger.Info(fmt.Sprintf("importPlaylist: user_id - %d, playlist_id - %s, url - %s", val, input.Id, input.Body.Url))
+
+	result, err := h.trackService.ImportPlaylist(ctx, input.Id, input.Body.Url, input.Body.Pending, val)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("importPlaylist error: user_id - %d, playlist_id - %s, url - %s", val, input.Id, input.Body.Url), zap.Error(err))
+
+		return nil, utils.Convert(err)
+	}
+
+	return &dto.PlaylistImportResponse{Body: result}, nil
+}
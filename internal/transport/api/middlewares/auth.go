@@ -1,48 +1,132 @@
 package middlewares
 
 import (
+	"backend/internal/infra"
 	"backend/internal/interfaces"
 	"backend/internal/service"
+	"backend/internal/transport/api/dto"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 	"go.uber.org/zap"
 )
 
 type Auth struct {
-	authService interfaces.AuthService
-	api         huma.API
-	logger      *zap.Logger
+	authService  interfaces.AuthService
+	tokenService interfaces.TokenService
+	api          huma.API
+	logger       *zap.Logger
+	admins       []int64
 }
 
-const UserJwtKey = "user"
+const (
+	UserJwtKey = "user"
+	SessionKey = "session"
+	GrantKey   = "grant"
+)
 
 // NewAuth - создать новый обработчик для middleware
-func NewAuth(authService *service.Auth, api huma.API, logger *zap.Logger) *Auth {
+func NewAuth(cfg *infra.Config, authService *service.Auth, tokenService *service.Token, api huma.API, logger *zap.Logger) *Auth {
 
 	return &Auth{
-		authService: authService,
-		api:         api,
-		logger:      logger,
+		authService:  authService,
+		tokenService: tokenService,
+		api:          api,
+		logger:       logger,
+		admins:       cfg.AdminIds,
 	}
 }
 
-// IsAuthenticated - проверить, авторизован ли пользователь для выполнения запроса
+// IsAuthenticated - проверить, авторизован ли пользователь для выполнения запроса. Принимает только JWT
 func (h *Auth) IsAuthenticated(ctx huma.Context, next func(ctx huma.Context)) {
 	authHeader := ctx.Header("Authorization")
 
 	// проверить токен
-	id, err := h.authService.VerifyToken(authHeader)
+	id, sessionID, err := h.authService.VerifyToken(ctx.Context(), authHeader)
 	if err != nil {
-		err := huma.WriteErr(h.api, ctx, 401, "unauthorized")
-		if err != nil {
-			h.logger.Error("failed to return status 401 from middleware: " + err.Error())
-			return
-		}
+		h.writeErr(ctx, 401, "unauthorized")
 		return
 	}
 
 	ctx = huma.WithValue(ctx, UserJwtKey, id)
+	ctx = huma.WithValue(ctx, SessionKey, sessionID)
 
 	// продолжить выполнение запроса
 	next(ctx)
 }
+
+// IsAdmin - как IsAuthenticated, но пускает только глобальных администраторов из конфига
+func (h *Auth) IsAdmin(ctx huma.Context, next func(ctx huma.Context)) {
+	h.IsAuthenticated(ctx, func(ctx huma.Context) {
+		id, ok := ctx.Context().Value(UserJwtKey).(int64)
+		if !ok || !slices.Contains(h.admins, id) {
+			h.writeErr(ctx, 403, "admin only")
+			return
+		}
+
+		h.logger.Info(fmt.Sprintf("admin request from %d: %s %s", id, ctx.Method(), ctx.URL().Path))
+
+		next(ctx)
+	})
+}
+
+/*
+Scoped - как IsAuthenticated, но также принимает API токены с правом scope.
+
+Если токен ограничен плейлистами, плейлист из пути (id или playlist_id) должен быть в списке
+*/
+func (h *Auth) Scoped(scope string) func(ctx huma.Context, next func(ctx huma.Context)) {
+	return func(ctx huma.Context, next func(ctx huma.Context)) {
+		token := strings.TrimSpace(strings.TrimPrefix(ctx.Header("Authorization"), "Bearer "))
+		if !strings.HasPrefix(token, service.ApiTokenPrefix) {
+			h.IsAuthenticated(ctx, next)
+			return
+		}
+
+		id, grant, err := h.tokenService.Verify(ctx.Context(), token)
+		if err != nil {
+			h.writeErr(ctx, 401, "unauthorized")
+			return
+		}
+
+		if !slices.Contains(grant.Scopes, scope) {
+			h.writeErr(ctx, 403, "token has no "+scope+" scope")
+			return
+		}
+
+		for _, param := range []string{"id", "playlist_id"} {
+			if playlistId := ctx.Param(param); playlistId != "" && !allowed(grant, playlistId) {
+				h.writeErr(ctx, 403, "token is not allowed for this playlist")
+				return
+			}
+		}
+
+		ctx = huma.WithValue(ctx, UserJwtKey, id)
+		ctx = huma.WithValue(ctx, GrantKey, grant)
+
+		next(ctx)
+	}
+}
+
+// AllowedPlaylist - доступен ли плейлист в запросе. Для JWT доступны все плейлисты юзера
+func AllowedPlaylist(ctx context.Context, playlistId string) bool {
+	grant, ok := ctx.Value(GrantKey).(dto.TokenGrant)
+	if !ok {
+		return true
+	}
+
+	return allowed(grant, playlistId)
+}
+
+func allowed(grant dto.TokenGrant, playlistId string) bool {
+	return len(grant.Playlists) == 0 || slices.Contains(grant.Playlists, playlistId)
+}
+
+func (h *Auth) writeErr(ctx huma.Context, status int, msg string) {
+	if err := huma.WriteErr(h.api, ctx, status, msg); err != nil {
+		h.logger.Error("failed to return status from middleware: " + err.Error())
+	}
+}
@@ -0,0 +1,19 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humaecho"
+)
+
+// BodyLimit - ограничить размер тела запроса. huma сама ограничивает только не-multipart тела (MaxBodyBytes),
+// multipart форма без ограничения прочиталась бы целиком
+func BodyLimit(limit int64) func(ctx huma.Context, next func(ctx huma.Context)) {
+	return func(ctx huma.Context, next func(ctx huma.Context)) {
+		c := humaecho.Unwrap(ctx)
+		c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, limit)
+
+		next(ctx)
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"backend/internal/transport/api/dto"
+	"backend/internal/transport/bot/utils"
+	"backend/pkg/i18n"
+	backendutils "backend/pkg/utils"
+	"backend/pkg/youtube"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/celestix/gotgproto/ext"
+	"github.com/gotd/td/tg"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	cardPrefix  = "card:"
+	cardApprove = "approve"
+	cardDecline = "decline"
+
+	// cardsLimit - для скольких последних треков помнить отправленные карточки
+	cardsLimit = 1024
+)
+
+type cardMessage struct {
+	chatId int64
+	msgId  int
+}
+
+/*
+sentCards - отправленные карточки по трекам, чтобы после решения обновить все копии, а не только нажатую.
+Хранятся в памяти: после перезапуска обновляется только нажатая карточка
+*/
+type sentCards struct {
+	mu       sync.Mutex
+	messages map[string][]cardMessage
+	order    []string
+}
+
+func newSentCards() *sentCards {
+	return &sentCards{messages: make(map[string][]cardMessage)}
+}
+
+func (c *sentCards) add(playlistId, trackId string, messages []cardMessage) {
+	key := playlistId + ":" + trackId
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.messages[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.messages[key] = append(c.messages[key], messages...)
+
+	for len(c.order) > cardsLimit {
+		delete(c.messages, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// take - забрать карточки трека, повторное нажатие на другой копии их уже не найдёт
+func (c *sentCards) take(playlistId, trackId string) []cardMessage {
+	key := playlistId + ":" + trackId
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messages := c.messages[key]
+	delete(c.messages, key)
+	if i := slices.Index(c.order, key); i >= 0 {
+		c.order = slices.Delete(c.order, i, i+1)
+	}
+
+	return messages
+}
+
+// listenSubmissions - отправлять карточки модерации для новых треков, пока работает бот
+func (b *Bot) listenSubmissions() {
+	ctx := b.client.CreateContext()
+
+	for submission := range b.submissions.Listen() {
+		b.sendCard(ctx, submission)
+	}
+}
+
+/*
+sendCard - карточка трека с кнопками Одобрить/Отклонить. В режиме group карточка уходит в группу плейлиста,
+а у плейлиста темы форума - в эту тему. В режиме dm (и для плейлистов без группы) - в личку каждому, кто может одобрять треки.
+В личку бот может написать только тем, кто его запускал. Карточка на языке плейлиста
+*/
+func (b *Bot) sendCard(ctx *ext.Context, submission dto.Submission) {
+	if b.cardsMode == "off" {
+		return
+	}
+
+	name := dto.DisplayName(submission.UserId, "", "", "")
+	if user, err := b.userService.Get(ctx, submission.UserId); err == nil {
+		name = user.Name
+	}
+
+	chats := []int64{submission.TelegramId}
+	topicID := submission.TopicId
+	if b.cardsMode == "dm" || submission.TelegramId == 0 {
+		moderators, err := b.accessService.Holders(ctx, submission.PlaylistId, dto.CapApprove)
+		if err != nil {
+			b.logger.Error(err.Error())
+			return
+		}
+		chats = moderators
+		topicID = 0
+	}
+
+	language := i18n.Match(submission.Language)
+	text := i18n.T(language, "card.text", submission.PlaylistTitle,
+		submission.Track.Authors, submission.Track.Title, youtube.TrackURL(submission.Track.Id), name)
+
+	markup := &tg.ReplyInlineMarkup{Rows: []tg.KeyboardButtonRow{{Buttons: []tg.KeyboardButtonClass{
+		&tg.KeyboardButtonCallback{Text: i18n.T(language, "card.approve"), Data: cardData(cardApprove, submission.PlaylistId, submission.Track.Id)},
+		&tg.KeyboardButtonCallback{Text: i18n.T(language, "card.decline"), Data: cardData(cardDecline, submission.PlaylistId, submission.Track.Id)},
+	}}}}
+
+	sent := make([]cardMessage, 0, len(chats))
+	for _, chat := range chats {
+		msg, err := ctx.SendMessage(chat, &tg.MessagesSendMessageRequest{
+			Message:     text,
+			ReplyTo:     utils.TopicReplyTo(topicID),
+			ReplyMarkup: markup,
+			NoWebpage:   true,
+		})
+		if err != nil {
+			b.logger.Warn("failed to send moderation card to " + strconv.FormatInt(chat, 10) + ": " + err.Error())
+			continue
+		}
+		sent = append(sent, cardMessage{chatId: chat, msgId: msg.ID})
+	}
+
+	b.cards.add(submission.PlaylistId, submission.Track.Id, sent)
+}
+
+/*
+handleCard - нажатие кнопки на карточке: решение принимается с правами нажавшего,
+все копии карточки (в личке у каждого модератора) дополняются решением
+*/
+func (b *Bot) handleCard(ctx *ext.Context, update *ext.Update) error {
+	query := update.CallbackQuery
+	language := b.lang(ctx, update)
+
+	action, playlistId, trackId, ok := parseCardData(query.Data)
+	if !ok {
+		b.answerCard(ctx, query, i18n.T(language, "card.outdated"))
+		return nil
+	}
+
+	var err error
+	var decision string
+	switch action {
+	case cardApprove:
+		err = b.trackService.Approve(ctx, playlistId, trackId, query.UserID)
+		decision = i18n.T(language, "card.approved")
+	case cardDecline:
+		err = b.trackService.Decline(ctx, playlistId, trackId, query.UserID)
+		decision = i18n.T(language, "card.declined")
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, backendutils.ErrNotEnoughPerms):
+		b.answerCard(ctx, query, i18n.T(language, "common.not_enough_perms"))
+		return nil
+	case errors.Is(err, pgx.ErrNoRows):
+		b.answerCard(ctx, query, i18n.T(language, "card.already_reviewed"))
+		return nil
+	default:
+		b.logger.Error(err.Error())
+		b.answerCard(ctx, query, i18n.T(language, "common.failed"))
+		return nil
+	}
+
+	b.logger.Info("moderation card: playlistID: " + playlistId + ", trackID: " + trackId + ", action: " + action + ", userID: " + strconv.FormatInt(query.UserID, 10))
+
+	chatId := update.EffectiveChat().GetID()
+
+	text := ""
+	messages, err := ctx.GetMessages(chatId, []tg.InputMessageClass{&tg.InputMessageID{ID: query.MsgID}})
+	if err != nil {
+		b.logger.Error(err.Error())
+	}
+	for _, message := range messages {
+		if msg, ok := message.(*tg.Message); ok {
+			text = msg.Message
+		}
+	}
+
+	name := dto.DisplayName(query.UserID, "", "", "")
+	if user := update.EffectiveUser(); user != nil {
+		name = dto.DisplayName(user.ID, user.FirstName, user.LastName, user.Username)
+	}
+
+	cards := b.cards.take(playlistId, trackId)
+	pressed := cardMessage{chatId: chatId, msgId: query.MsgID}
+	if !slices.Contains(cards, pressed) {
+		cards = append(cards, pressed)
+	}
+
+	// без ReplyMarkup кнопки убираются. Текст у всех копий одинаковый, берётся с нажатой
+	for _, card := range cards {
+		if _, err := ctx.EditMessage(card.chatId, &tg.MessagesEditMessageRequest{
+			ID:        card.msgId,
+			Message:   strings.TrimSpace(text + "\n\n" + decision + ": " + name),
+			NoWebpage: true,
+		}); err != nil {
+			b.logger.Warn("failed to edit moderation card in " + strconv.FormatInt(card.chatId, 10) + ": " + err.Error())
+		}
+	}
+
+	b.answerCard(ctx, query, i18n.T(language, "common.done"))
+
+	return nil
+}
+
+func (b *Bot) answerCard(ctx *ext.Context, query *tg.UpdateBotCallbackQuery, text string) {
+	if _, err := ctx.AnswerCallback(&tg.MessagesSetBotCallbackAnswerRequest{
+		QueryID: query.QueryID,
+		Message: text,
+	}); err != nil {
+		b.logger.Error(err.Error())
+	}
+}
+
+// cardData - данные кнопки: card:<действие>:<плейлист>:<трек>, укладываются в лимит Telegram в 64 байта
+func cardData(action, playlistId, trackId string) []byte {
+	return []byte(cardPrefix + action + ":" + playlistId + ":" + trackId)
+}
+
+func parseCardData(data []byte) (string, string, string, bool) {
+	parts := strings.Split(strings.TrimPrefix(string(data), cardPrefix), ":")
+	if len(parts) != 3 || (parts[0] != cardApprove && parts[0] != cardDecline) {
+		return "", "", "", false
+	}
+
+	return parts[0], parts[1], parts[2], true
+}
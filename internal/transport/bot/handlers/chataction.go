@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"backend/internal/transport/bot/utils"
 	"context"
 	"errors"
 	"strconv"
@@ -40,90 +41,69 @@ func (b *Bot) handleChatAction(ctx *ext.Context, update *ext.Update) error {
 
 	switch smResult := serviceMessage.Action.(type) {
 	case *tg.MessageActionChatEditTitle:
-		err := b.handleTitleUpdate(ctx.Context, smResult.Title, id)
+		err := b.handleTitleUpdate(ctx.Context, smResult.Title, id, 0, actorID(serviceMessage))
 		if err != nil {
 			b.logger.Error(err.Error())
 		}
-		/*
-			case *tg.MessageActionChatEditPhoto:
-					err := b.handlePhotoUpdate(ctx, smResult.Photo, id)
-					if err != nil {
-						b.logger.Error(err.Error())
-					}
-		*/
+	// меняют только иконку или статус темы - названия нет
+	case *tg.MessageActionTopicEdit:
+		if title, ok := smResult.GetTitle(); ok {
+			err := b.handleTitleUpdate(ctx.Context, title, id, utils.TopicID(serviceMessage.ReplyTo), actorID(serviceMessage))
+			if err != nil {
+				b.logger.Error(err.Error())
+			}
+		}
+	// при миграции приходят оба сообщения: в старую группу и в новую супергруппу, второе ничего не меняет
+	case *tg.MessageActionChatMigrateTo:
+		if _, err := b.handleMigration(ctx.Context, id, smResult.ChannelID); err != nil {
+			b.logger.Error(err.Error())
+		}
+	case *tg.MessageActionChannelMigrateFrom:
+		if _, err := b.handleMigration(ctx.Context, smResult.ChatID, id); err != nil {
+			b.logger.Error(err.Error())
+		}
+	case *tg.MessageActionChatEditPhoto:
+		if err := b.handlePhotoUpdate(ctx.Context, smResult.Photo.GetID(), id); err != nil {
+			b.logger.Error(err.Error())
+		}
+	case *tg.MessageActionChatDeletePhoto:
+		if err := b.handlePhotoUpdate(ctx.Context, 0, id); err != nil {
+			b.logger.Error(err.Error())
+		}
 	}
 
 	return nil
 }
 
-/*
-func (b *Bot) handlePhotoUpdate(ctx context.Context, photo tg.PhotoClass, chatID int64) error {
-	playlistID, err := b.permissionService.Get(ctx, chatID, dto.GroupRole)
+// handlePhotoUpdate - фото группы изменили или удалили (photoID == 0): обновить обложку плейлиста всей группы
+func (b *Bot) handlePhotoUpdate(ctx context.Context, photoID, chatID int64) error {
+	playlist, err := b.playlistService.GetByGroup(ctx, chatID, 0)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil
 		}
-		b.logger.Error(err.Error())
 		return err
 	}
 
-	switch photoResult := photo.(type) {
-	case *tg.Photo:
-		bestSize := photoResult.Sizes[len(photoResult.Sizes)-1].(*tg.PhotoSize)
-
-		if bestSize == nil {
-			return errors.New("no suitable size found")
-		}
-
-		loc := &tg.InputPhotoFileLocation{
-			ID:            photoResult.ID,
-			AccessHash:    photoResult.AccessHash,
-			FileReference: photoResult.FileReference,
-			ThumbSize:     bestSize.Type,
-		}
-
-		pr, pw := io.Pipe()
-		_, err = b.dl.Download(b.client.Client.API(), loc).Stream(ctx, pw)
-		if err != nil {
-			return err
-		}
-		err = pw.Close()
-		if err != nil {
-			return err
-		}
-
-		_, err = b.s3.UploadPhoto(ctx, playlistID, pr)
-		if err != nil {
-			return err
-		}
-
-		err = pr.Close()
-		if err != nil {
-			return err
-		}
-
-		err = b.playlistService.UpdatePhoto(ctx, playlistID, playlistID, chatID)
-		if err != nil {
-			return err
-		}
-	case *tg.PhotoEmpty:
-		err = b.s3.DeletePhoto(ctx, playlistID)
-		if err != nil {
-			return err
-		}
-
-		err = b.playlistService.UpdatePhoto(ctx, playlistID, "", chatID)
+	var photo []byte
+	if photoID != 0 {
+		photo, err = utils.DownloadChatPhoto(b.client, ctx, chatID, photoID)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	b.logger.Info("group photo updated: playlistID: " + playlist.Id + ", chatID: " + strconv.FormatInt(chatID, 10) + ", size: " + strconv.Itoa(len(photo)))
+
+	return b.playlistService.UpdatePhoto(ctx, playlist.Id, photo)
 }
-*/
 
-func (b *Bot) handleTitleUpdate(ctx context.Context, title string, chatID int64) error {
-	playlist, err := b.playlistService.GetByGroup(ctx, chatID)
+/*
+handleTitleUpdate - группу или тему (topicID != 0) переименовали: переименовать её плейлист от имени того, кто переименовал.
+Тема без своего плейлиста не трогает плейлист группы
+*/
+func (b *Bot) handleTitleUpdate(ctx context.Context, title string, chatID int64, topicID int, userID int64) error {
+	playlist, err := b.playlistService.GetByGroup(ctx, chatID, topicID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil
@@ -131,11 +111,49 @@ func (b *Bot) handleTitleUpdate(ctx context.Context, title string, chatID int64)
 		b.logger.Error(err.Error())
 		return err
 	}
+	if playlist.TopicId != topicID {
+		return nil
+	}
 
-	err = b.playlistService.Rename(ctx, playlist.Id, title, chatID)
+	err = b.playlistService.Rename(ctx, playlist.Id, title, userID)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// actorID - автор сервисного сообщения, 0 - если его нет (например, сообщение от имени группы)
+func actorID(msg *tg.MessageService) int64 {
+	if user, ok := msg.FromID.(*tg.PeerUser); ok {
+		return user.UserID
+	}
+
+	return 0
+}
+
+/*
+handleMigration - обычную группу превратили в супергруппу с новым ID: перенести плейлист на новый ID
+и пересобрать участников. Возвращает false, если у старой группы не было плейлиста
+*/
+func (b *Bot) handleMigration(ctx context.Context, oldChatID, newChatID int64) (bool, error) {
+	playlistId, migrated, err := b.playlistService.Migrate(ctx, oldChatID, newChatID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !migrated {
+		return true, nil
+	}
+
+	b.logger.Info("playlist migrated: playlistID: " + playlistId + ", chatID: " + strconv.FormatInt(oldChatID, 10) + " -> " + strconv.FormatInt(newChatID, 10))
+
+	if _, err := b.syncGroup(ctx, playlistId, newChatID); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"backend/internal/transport/api/dto"
+	"backend/internal/transport/bot/utils"
+	"backend/pkg/i18n"
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/celestix/gotgproto/ext"
+	"github.com/gotd/td/tg"
+	"github.com/jackc/pgx/v5"
+)
+
+// listLimit - сколько строк показывать в списках команд
+const listLimit = 10
+
+// команды для подсказок в группах и в личке, описания - в каталоге по ключу cmd.<команда>
+var (
+	groupCommands   = []string{"playlist", "pending", "top", "mine", "mute", "unmute", "sync", "lang", "newplaylist", "help"}
+	privateCommands = []string{"start", "help"}
+)
+
+// commandList - команды с описаниями на языке language
+func commandList(language string, names []string) []tg.BotCommand {
+	commands := make([]tg.BotCommand, len(names))
+	for i, name := range names {
+		commands[i] = tg.BotCommand{Command: name, Description: i18n.T(language, "cmd."+name)}
+	}
+
+	return commands
+}
+
+/*
+registerCommands - зарегистрировать команды в Telegram, чтобы они подсказывались при вводе /.
+Telegram показывает описания на языке юзера, пустой код языка - для остальных языков
+*/
+func (b *Bot) registerCommands() {
+	ctx := b.client.CreateContext()
+
+	for _, code := range append([]string{""}, i18n.Languages()...) {
+		language := i18n.Match(code)
+
+		for scope, names := range map[tg.BotCommandScopeClass][]string{
+			&tg.BotCommandScopeChats{}: groupCommands,
+			&tg.BotCommandScopeUsers{}: privateCommands,
+		} {
+			if _, err := ctx.Raw.BotsSetBotCommands(ctx, &tg.BotsSetBotCommandsRequest{
+				Scope:    scope,
+				LangCode: code,
+				Commands: commandList(language, names),
+			}); err != nil {
+				b.logger.Error("failed to register bot commands: " + err.Error())
+			}
+		}
+	}
+}
+
+// groupPlaylist - плейлист темы или всей группы, из которой пришла команда. Если его нет, бот отвечает об этом сам
+func (b *Bot) groupPlaylist(ctx *ext.Context, update *ext.Update) (dto.Playlist, bool) {
+	playlist, err := b.playlistService.GetByGroup(ctx, update.EffectiveChat().GetID(), b.topic(update))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			b.logger.Error(err.Error())
+		}
+		b.reply(ctx, update, "common.no_playlist")
+		return dto.Playlist{}, false
+	}
+
+	return playlist, true
+}
+
+// handlePlaylist - /playlist: название, количество треков, длительность и кнопка миниаппа
+func (b *Bot) handlePlaylist(ctx *ext.Context, update *ext.Update) error {
+	playlist, ok := b.groupPlaylist(ctx, update)
+	if !ok {
+		return nil
+	}
+
+	text := i18n.T(playlist.Language, "playlist.info",
+		playlist.Title, playlist.Count, playlist.AllowedCount, utils.FormatDuration(playlist.Length))
+
+	opts := &ext.ReplyOpts{}
+	if b.miniAppUrl != "" {
+		opts.Markup = &tg.ReplyInlineMarkup{Rows: []tg.KeyboardButtonRow{{Buttons: []tg.KeyboardButtonClass{
+			&tg.KeyboardButtonURL{Text: i18n.T(playlist.Language, "playlist.open"), URL: b.miniAppUrl + "?startapp=" + playlist.Id},
+		}}}}
+	}
+
+	if _, err := ctx.Reply(update, ext.ReplyTextString(text), opts); err != nil {
+		b.logger.Error(err.Error())
+	}
+
+	return nil
+}
+
+// handlePending - /pending: треки на модерации, только для тех, кто может одобрять
+func (b *Bot) handlePending(ctx *ext.Context, update *ext.Update) error {
+	group, ok := b.groupPlaylist(ctx, update)
+	if !ok || update.EffectiveUser() == nil {
+		return nil
+	}
+
+	playlist, err := b.playlistService.GetById(ctx, group.Id, update.EffectiveUser().ID)
+	if err != nil {
+		b.replyModerationError(ctx, update, err)
+		return nil
+	}
+
+	if !slices.Contains(playlist.Capabilities, dto.CapApprove) {
+		b.reply(ctx, update, "common.not_enough_perms")
+		return nil
+	}
+
+	var lines []string
+	for _, track := range playlist.Tracks {
+		if !slices.Contains(playlist.AllowedIds, track.Id) {
+			lines = append(lines, track.Authors+" - "+track.Title)
+		}
+	}
+
+	if len(lines) == 0 {
+		b.reply(ctx, update, "pending.empty")
+		return nil
+	}
+
+	count := len(lines)
+	if len(lines) > listLimit {
+		lines = append(lines[:listLimit], "...")
+	}
+
+	b.reply(ctx, update, "pending.list", count, strings.Join(lines, "\n"))
+
+	return nil
+}
+
+// handleTop - /top: исполнители, чьи треки предлагали чаще всего
+func (b *Bot) handleTop(ctx *ext.Context, update *ext.Update) error {
+	playlist, ok := b.groupPlaylist(ctx, update)
+	if !ok {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, track := range playlist.Tracks {
+		for _, author := range splitAuthors(track.Authors) {
+			counts[author]++
+		}
+	}
+
+	if len(counts) == 0 {
+		b.reply(ctx, update, "top.empty")
+		return nil
+	}
+
+	authors := make([]string, 0, len(counts))
+	for author := range counts {
+		authors = append(authors, author)
+	}
+	slices.SortFunc(authors, func(a, b string) int {
+		return cmp.Or(counts[b]-counts[a], strings.Compare(a, b))
+	})
+
+	lines := make([]string, 0, listLimit)
+	for i, author := range authors[:min(len(authors), listLimit)] {
+		lines = append(lines, fmt.Sprintf("%d. %s - %d", i+1, author, counts[author]))
+	}
+
+	b.reply(ctx, update, "top.list", strings.Join(lines, "\n"))
+
+	return nil
+}
+
+// handleMine - /mine: треки, предложенные отправителем, и их статусы
+func (b *Bot) handleMine(ctx *ext.Context, update *ext.Update) error {
+	playlist, ok := b.groupPlaylist(ctx, update)
+	if !ok || update.EffectiveUser() == nil {
+		return nil
+	}
+
+	submissions, err := b.trackService.Mine(ctx, playlist.Id, listLimit, update.EffectiveUser().ID)
+	if err != nil {
+		b.replyModerationError(ctx, update, err)
+		return nil
+	}
+
+	if len(submissions) == 0 {
+		b.reply(ctx, update, "mine.empty")
+		return nil
+	}
+
+	lines := make([]string, len(submissions))
+	for i, submission := range submissions {
+		lines[i] = submission.Authors + " - " + submission.Title + ": " + i18n.T(playlist.Language, "status."+submission.Status)
+	}
+
+	b.reply(ctx, update, "mine.list", strings.Join(lines, "\n"))
+
+	return nil
+}
+
+// handleHelp - /help: список команд
+func (b *Bot) handleHelp(ctx *ext.Context, update *ext.Update) error {
+	language := b.lang(ctx, update)
+
+	lines := make([]string, len(groupCommands))
+	for i, command := range commandList(language, groupCommands) {
+		lines[i] = "/" + command.Command + " - " + command.Description
+	}
+
+	b.reply(ctx, update, "help.text", strings.Join(lines, "\n"), ctx.Self.Username)
+
+	return nil
+}
+
+// splitAuthors - исполнители трека по отдельности: "A, B & C" -> A, B, C
+func splitAuthors(authors string) []string {
+	var result []string
+	for _, part := range strings.Split(strings.ReplaceAll(authors, " & ", ", "), ", ") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+
+	return result
+}
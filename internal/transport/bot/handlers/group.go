@@ -3,12 +3,13 @@ package handlers
 import (
 	"backend/internal/infra/queries"
 	"backend/internal/transport/bot/utils"
+	"backend/pkg/i18n"
+	backendutils "backend/pkg/utils"
 	"errors"
 	"strconv"
 
 	"github.com/celestix/gotgproto/ext"
 	"github.com/gotd/td/tg"
-	"github.com/jackc/pgx/v5"
 )
 
 func (b *Bot) handleGroup(ctx *ext.Context, update *ext.Update) error {
@@ -21,9 +22,14 @@ func (b *Bot) handleGroup(ctx *ext.Context, update *ext.Update) error {
 	b.logger.Info("Handling group update. ChatID: " + strconv.FormatInt(data.ChatID, 10) + ", UserID: " + strconv.FormatInt(data.UserID, 10) + ", PrevRole: " + string(data.PrevRole) + ", NewRole: " + string(data.NewRole))
 
 	if data.UserID == b.client.Self.ID {
+		// пока у группы нет плейлиста, бот говорит на языке добавившего его админа
+		language := b.userLang(ctx, utils.UpdateProfile(update, data.ActorID).LanguageCode, data.ActorID)
+		if existing, err := b.playlistService.GroupLanguage(ctx, data.ChatID, 0); err == nil {
+			language = existing
+		}
+
 		if data.PrevRole == "" {
-			_, err := ctx.SendMessage(data.ChatID, &tg.MessagesSendMessageRequest{Message: "Привет, я Лотти - бот для управления плейлистами!" + "\n\n" +
-				"Можешь дать мне права администратора (чтобы я мог видеть лог AKA recent actions/недавние действия, а также всех участников и администраторов)"})
+			_, err := ctx.SendMessage(data.ChatID, &tg.MessagesSendMessageRequest{Message: i18n.T(language, "group.welcome")})
 			if err != nil {
 				b.logger.Error(err.Error())
 			}
@@ -31,12 +37,17 @@ func (b *Bot) handleGroup(ctx *ext.Context, update *ext.Update) error {
 		}
 
 		if data.PrevRole == queries.PlaylistRoleViewer && data.NewRole == queries.PlaylistRoleModerator {
-			_, err := ctx.SendMessage(data.ChatID, &tg.MessagesSendMessageRequest{Message: "Респект тебе за админку, сейчас создам плейлист!"})
+			_, err := ctx.SendMessage(data.ChatID, &tg.MessagesSendMessageRequest{Message: i18n.T(language, "group.admin_thanks")})
 			if err != nil {
 				b.logger.Error(err.Error())
 				return err
 			}
 
+			// у группы уже есть плейлист (например, бота повторно сделали админом)
+			if _, err := b.playlistService.GetByGroup(ctx, data.ChatID, 0); err == nil {
+				return nil
+			}
+
 			// get basic chat info
 			chat, err := utils.GetChatInfo(b.client, ctx.Context, data.ChatID, data.ActorID)
 			if err != nil {
@@ -44,10 +55,22 @@ func (b *Bot) handleGroup(ctx *ext.Context, update *ext.Update) error {
 				return err
 			}
 
-			// TODO: handle group avatar, set type
+			// супергруппа из обычной группы: забрать плейлист старой группы вместо создания нового
+			if chat.MigratedFrom != 0 {
+				found, err := b.handleMigration(ctx, chat.MigratedFrom, data.ChatID)
+				if err != nil {
+					b.logger.Error(err.Error())
+					return err
+				}
+				if found {
+					return nil
+				}
+			}
+
+			// TODO: set type
 
 			// create playlist
-			create, err := b.playlistService.Create(ctx.Context, chat.Title, queries.PlaylistTypeYoutube, data.ChatID)
+			create, err := b.playlistService.Create(ctx.Context, chat.Title, queries.PlaylistTypeYoutube, data.ChatID, 0)
 			if err != nil {
 				b.logger.Error(err.Error())
 				return err
@@ -59,52 +82,93 @@ func (b *Bot) handleGroup(ctx *ext.Context, update *ext.Update) error {
 				return err
 			}
 
+			if err := b.playlistService.SetLanguage(ctx, create.Id, language); err != nil {
+				b.logger.Error(err.Error())
+			}
+
+			// без обложки плейлист всё равно работает, поэтому ошибка только логируется
+			if photo, ok := chat.Photo.(*tg.ChatPhoto); ok {
+				if err := b.handlePhotoUpdate(ctx, photo.PhotoID, data.ChatID); err != nil {
+					b.logger.Error(err.Error())
+				}
+			}
+
 			return nil
 		}
 
 		if data.NewRole == "" || data.NewRole == queries.PlaylistRoleViewer {
-			playlist, err := b.playlistService.GetByGroup(ctx, data.ChatID)
-			if err != nil {
-				b.logger.Error(err.Error())
-				return err
+			// при миграции бот пропадает из старой группы - плейлист переезжает, а не удаляется
+			if _, ok := update.UpdateClass.(*tg.UpdateChatParticipant); ok {
+				newChatID, err := utils.MigratedTo(b.client, ctx, data.ChatID)
+				if err != nil {
+					b.logger.Error(err.Error())
+				}
+				if newChatID != 0 {
+					if _, err := b.handleMigration(ctx, data.ChatID, newChatID); err != nil {
+						b.logger.Error(err.Error())
+						return err
+					}
+					return nil
+				}
 			}
 
-			err = b.playlistService.Delete(ctx.Context, playlist.Id)
+			// вместе с плейлистом группы удаляются плейлисты её тем
+			playlists, err := b.playlistService.ChatPlaylists(ctx, data.ChatID)
 			if err != nil {
 				b.logger.Error(err.Error())
 				return err
 			}
 
+			for _, playlistId := range playlists {
+				err = b.playlistService.Delete(ctx.Context, playlistId)
+				if err != nil {
+					b.logger.Error(err.Error())
+					return err
+				}
+			}
+
 			return nil
 		}
 	} else {
-		playlist, err := b.playlistService.GetByGroup(ctx, data.ChatID)
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return nil
+		// профиль обновляется при любом изменении участника, даже если плейлиста у группы нет
+		if data.Profile.ID != 0 {
+			if err := b.userService.Upsert(ctx, data.Profile); err != nil {
+				b.logger.Error(err.Error())
+				return err
 			}
+		}
+
+		// участники общие для всей группы: изменение применяется к плейлистам всех тем
+		playlists, err := b.playlistService.ChatPlaylists(ctx, data.ChatID)
+		if err != nil {
 			b.logger.Error(err.Error())
 			return err
 		}
 
-		if data.NewRole == "" {
-			err = b.permissionService.Remove(ctx, playlist.Id, data.UserID)
-			if err != nil {
-				b.logger.Error(err.Error())
-				return err
-			}
-		} else {
-			if data.PrevRole == "" {
-				err = b.permissionService.Add(ctx, data.NewRole, playlist.Id, data.UserID)
+		for _, playlistId := range playlists {
+			if data.NewRole == "" {
+				err = b.permissionService.Remove(ctx, playlistId, data.UserID)
 				if err != nil {
 					b.logger.Error(err.Error())
 					return err
 				}
 			} else {
-				err = b.permissionService.Edit(ctx, data.NewRole, playlist.Id, data.UserID)
-				if err != nil {
-					b.logger.Error(err.Error())
-					return err
+				if data.PrevRole == "" {
+					err = b.permissionService.Add(ctx, data.NewRole, playlistId, data.UserID)
+					if err != nil {
+						b.logger.Error(err.Error())
+						return err
+					}
+				} else {
+					err = b.permissionService.Edit(ctx, data.NewRole, playlistId, data.UserID)
+					if errors.Is(err, backendutils.ErrOwnerKept) {
+						b.logger.Info("owner role kept: playlistID: " + playlistId + ", userID: " + strconv.FormatInt(data.UserID, 10) + ", chat role: " + string(data.NewRole))
+						continue
+					}
+					if err != nil {
+						b.logger.Error(err.Error())
+						return err
+					}
 				}
 			}
 		}
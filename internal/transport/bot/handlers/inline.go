@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	backendutils "backend/pkg/utils"
+	"backend/pkg/youtube"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/celestix/gotgproto/ext"
+	"github.com/celestix/gotgproto/types"
+	"github.com/gotd/td/tg"
+	"github.com/jackc/pgx/v5"
+)
+
+// inlineCacheTime - сколько секунд Telegram кеширует ответ на одинаковый запрос
+const inlineCacheTime = 300
+
+// handleInline - @bot запрос: найти треки и показать их карточками. Сообщение содержит ссылку на трек,
+// по ней бот узнаёт трек, когда результат отправлен в группу (см. handleInlineSent)
+func (b *Bot) handleInline(ctx *ext.Context, update *ext.Update) error {
+	query := update.InlineQuery
+
+	var results []tg.InputBotInlineResultClass
+	if text := strings.TrimSpace(query.Query); text != "" {
+		tracks, err := b.trackService.Search(ctx, text)
+		if err != nil {
+			b.logger.Error(err.Error())
+		}
+
+		for _, track := range tracks {
+			result := &tg.InputBotInlineResult{
+				ID:          track.Id,
+				Type:        "article",
+				Title:       track.Title,
+				Description: track.Authors,
+				URL:         youtube.TrackURL(track.Id),
+				SendMessage: &tg.InputBotInlineMessageText{
+					Message: track.Authors + " - " + track.Title + "\n" + youtube.TrackURL(track.Id),
+				},
+			}
+
+			if track.Thumbnail != "" {
+				result.Thumb = tg.InputWebDocument{
+					URL:      track.Thumbnail,
+					MimeType: "image/jpeg",
+				}
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	if _, err := ctx.SetInlineBotResult(&tg.MessagesSetInlineBotResultsRequest{
+		QueryID:   query.QueryID,
+		Results:   results,
+		CacheTime: inlineCacheTime,
+	}); err != nil {
+		b.logger.Error(err.Error())
+	}
+
+	return nil
+}
+
+// isInlineSent - сообщение отправлено через inline режим этого бота
+func (b *Bot) isInlineSent(msg *types.Message) bool {
+	return msg.ViaBotID == b.client.Self.ID
+}
+
+// handleInlineSent - результат inline режима отправлен в группу: предложить трек в плейлист группы от имени отправителя
+func (b *Bot) handleInlineSent(ctx *ext.Context, update *ext.Update) error {
+	user := update.EffectiveUser()
+	if user == nil {
+		return nil
+	}
+
+	var trackId string
+	for _, field := range strings.Fields(update.EffectiveMessage.Text) {
+		if id, ok := youtube.ParseTrackID(field); ok {
+			trackId = id
+			break
+		}
+	}
+	if trackId == "" {
+		return nil
+	}
+
+	playlist, err := b.playlistService.GetByGroup(ctx, update.EffectiveChat().GetID(), b.topic(update))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			b.logger.Error(err.Error())
+		}
+		return nil
+	}
+
+	b.logger.Info("inline submit: chatID: " + strconv.FormatInt(update.EffectiveChat().GetID(), 10) + ", userID: " + strconv.FormatInt(user.ID, 10) + ", trackID: " + trackId)
+
+	err = b.trackService.Submit(ctx, playlist.Id, trackId, user.ID)
+	switch {
+	case err == nil:
+		b.reply(ctx, update, "inline.submitted")
+	case errors.Is(err, backendutils.ErrMuted):
+		b.reply(ctx, update, "inline.muted")
+	case errors.Is(err, backendutils.ErrNotEnoughPerms):
+		b.reply(ctx, update, "common.not_enough_perms")
+	case errors.Is(err, pgx.ErrNoRows):
+		b.reply(ctx, update, "inline.not_member")
+	default:
+		b.logger.Error(err.Error())
+		b.reply(ctx, update, "common.failed")
+	}
+
+	return nil
+}
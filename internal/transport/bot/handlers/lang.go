@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"backend/pkg/i18n"
+	"context"
+	"strings"
+
+	"github.com/celestix/gotgproto/ext"
+)
+
+/*
+lang - язык ответа: в группе с плейлистом - язык плейлиста темы или всей группы, иначе - язык Telegram отправителя.
+Неподдерживаемые языки заменяются на i18n.Default
+*/
+func (b *Bot) lang(ctx context.Context, update *ext.Update) string {
+	if chat := update.EffectiveChat(); chat != nil && chat.GetID() != 0 {
+		if language, err := b.playlistService.GroupLanguage(ctx, chat.GetID(), b.topic(update)); err == nil {
+			return language
+		}
+	}
+
+	if user := update.EffectiveUser(); user != nil {
+		return i18n.Match(user.LangCode)
+	}
+
+	return i18n.Default
+}
+
+// userLang - язык юзера: из апдейта, если Telegram его прислал, иначе сохранённый в профиле
+func (b *Bot) userLang(ctx context.Context, code string, userId int64) string {
+	if code == "" {
+		if user, err := b.userService.Get(ctx, userId); err == nil {
+			code = user.LanguageCode
+		}
+	}
+
+	return i18n.Match(code)
+}
+
+// handleLang - /lang: показать язык бота в группе, /lang <код> - сменить. Менять могут только админы группы
+func (b *Bot) handleLang(ctx *ext.Context, update *ext.Update) error {
+	playlist, ok := b.groupPlaylist(ctx, update)
+	if !ok || update.EffectiveUser() == nil {
+		return nil
+	}
+
+	args := update.Args()[1:]
+	if len(args) == 0 {
+		b.reply(ctx, update, "lang.current", i18n.T(playlist.Language, "language.name"), languageList())
+		return nil
+	}
+
+	language := strings.ToLower(args[0])
+	if !i18n.Supported(language) {
+		b.reply(ctx, update, "lang.unknown", languageList())
+		return nil
+	}
+
+	if _, ok := b.requireGroupAdmin(ctx, update); !ok {
+		return nil
+	}
+
+	if err := b.playlistService.SetLanguage(ctx, playlist.Id, language); err != nil {
+		b.replyModerationError(ctx, update, err)
+		return nil
+	}
+
+	b.logger.Info("language changed: playlistID: " + playlist.Id + ", language: " + language)
+
+	b.reply(ctx, update, "lang.changed")
+
+	return nil
+}
+
+// languageList - доступные языки: "en (English), ru (Русский)"
+func languageList() string {
+	languages := i18n.Languages()
+	for i, language := range languages {
+		languages[i] = language + " (" + i18n.T(language, "language.name") + ")"
+	}
+
+	return strings.Join(languages, ", ")
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"backend/internal/transport/bot/utils"
+	"backend/pkg/i18n"
+	backendutils "backend/pkg/utils"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/celestix/gotgproto/ext"
+	"github.com/gotd/td/tg"
+	"github.com/jackc/pgx/v5"
+)
+
+// handleMute - /mute [длительность] [причина] в ответ на сообщение: запретить автору предлагать треки
+func (b *Bot) handleMute(ctx *ext.Context, update *ext.Update) error {
+	playlistId, targetId, ok := b.moderationTarget(ctx, update)
+	if !ok {
+		return nil
+	}
+
+	args := update.Args()[1:]
+	var duration time.Duration
+	if len(args) > 0 {
+		if d, ok := utils.ParseDuration(args[0]); ok {
+			duration = d
+			args = args[1:]
+		}
+	}
+
+	err := b.moderationService.Mute(ctx, playlistId, targetId, duration, strings.Join(args, " "), update.EffectiveUser().ID)
+	if err != nil {
+		b.replyModerationError(ctx, update, err)
+		return nil
+	}
+
+	if duration > 0 {
+		b.reply(ctx, update, "mute.done_for", duration.String())
+	} else {
+		b.reply(ctx, update, "mute.done")
+	}
+
+	return nil
+}
+
+// handleUnmute - /unmute в ответ на сообщение: снять запрет
+func (b *Bot) handleUnmute(ctx *ext.Context, update *ext.Update) error {
+	playlistId, targetId, ok := b.moderationTarget(ctx, update)
+	if !ok {
+		return nil
+	}
+
+	if err := b.moderationService.Unmute(ctx, playlistId, targetId, update.EffectiveUser().ID); err != nil {
+		b.replyModerationError(ctx, update, err)
+		return nil
+	}
+
+	b.reply(ctx, update, "unmute.done")
+
+	return nil
+}
+
+// moderationTarget - плейлист группы и автор сообщения, на которое ответили командой
+func (b *Bot) moderationTarget(ctx *ext.Context, update *ext.Update) (string, int64, bool) {
+	if update.EffectiveUser() == nil {
+		return "", 0, false
+	}
+
+	playlist, ok := b.groupPlaylist(ctx, update)
+	if !ok {
+		return "", 0, false
+	}
+
+	msg := update.EffectiveMessage
+	if err := msg.SetRepliedToMessage(ctx, ctx.Raw, ctx.PeerStorage); err != nil || msg.ReplyToMessage == nil {
+		b.reply(ctx, update, "moderation.reply_needed")
+		return "", 0, false
+	}
+
+	from, ok := msg.ReplyToMessage.FromID.(*tg.PeerUser)
+	if !ok {
+		b.reply(ctx, update, "moderation.reply_needed")
+		return "", 0, false
+	}
+
+	b.logger.Info("moderation command: chatID: " + strconv.FormatInt(update.EffectiveChat().GetID(), 10) + ", targetID: " + strconv.FormatInt(from.UserID, 10))
+
+	return playlist.Id, from.UserID, true
+}
+
+func (b *Bot) replyModerationError(ctx *ext.Context, update *ext.Update, err error) {
+	switch {
+	case errors.Is(err, backendutils.ErrNotEnoughPerms):
+		b.reply(ctx, update, "common.not_enough_perms")
+	case errors.Is(err, pgx.ErrNoRows):
+		b.reply(ctx, update, "moderation.member_not_found")
+	case errors.Is(err, backendutils.ErrInvalidInput):
+		b.reply(ctx, update, "moderation.invalid", err.Error())
+	default:
+		b.logger.Error(err.Error())
+		b.reply(ctx, update, "common.failed")
+	}
+}
+
+// reply - ответить текстом из каталога на языке чата (см. lang)
+func (b *Bot) reply(ctx *ext.Context, update *ext.Update, key string, args ...any) {
+	text := i18n.T(b.lang(ctx, update), key, args...)
+	if _, err := ctx.Reply(update, ext.ReplyTextString(text), &ext.ReplyOpts{}); err != nil {
+		b.logger.Error(err.Error())
+	}
+}
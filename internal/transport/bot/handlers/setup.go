@@ -4,9 +4,11 @@ import (
 	"backend/internal/infra"
 	"backend/internal/interfaces"
 	"backend/internal/service"
+	"time"
 
 	"github.com/celestix/gotgproto"
 	"github.com/celestix/gotgproto/dispatcher/handlers"
+	"github.com/celestix/gotgproto/dispatcher/handlers/filters"
 	"github.com/celestix/gotgproto/sessionMaker"
 	"github.com/celestix/gotgproto/types"
 	"github.com/gotd/td/telegram/dcs"
@@ -16,16 +18,23 @@ import (
 )
 
 type Bot struct {
+	userService       interfaces.UserService
 	playlistService   interfaces.PlaylistService
 	permissionService interfaces.PermissionService
+	moderationService interfaces.ModerationService
+	trackService      interfaces.TrackService
+	accessService     interfaces.AccessService
+	submissions       *service.Submissions
+	cards             *sentCards
+	cardsMode         string
+	miniAppUrl        string
+	syncInterval      time.Duration
 	logger            *zap.Logger
-	// dl                *downloader.Downloader
-	// s3                *service.S3Service
 
 	client *gotgproto.Client
 }
 
-func New(cfg *infra.Config, playlistService *service.Playlist, permissionService *service.Permission, logger *zap.Logger) (*Bot, error) {
+func New(cfg *infra.Config, userService *service.User, playlistService *service.Playlist, permissionService *service.Permission, moderationService *service.Moderation, trackService *service.Track, accessService *service.Access, submissions *service.Submissions, logger *zap.Logger) (*Bot, error) {
 	var dcList dcs.List
 
 	if cfg.Debug {
@@ -52,38 +61,64 @@ func New(cfg *infra.Config, playlistService *service.Playlist, permissionService
 	self := client.Self
 	logger.Info("bot logged in as https://t.me/" + self.Username)
 
-	/*
-		s3Service, err := service.NewS3Service(app)
-		if err != nil {
-			return nil, err
-		}
-	*/
-
 	return &Bot{
+		userService:       userService,
 		playlistService:   playlistService,
 		permissionService: permissionService,
-		// 	dl:                downloader.NewDownloader(),
-		//	s3:                s3Service,
-		client: client,
-		logger: logger,
+		moderationService: moderationService,
+		trackService:      trackService,
+		accessService:     accessService,
+		submissions:       submissions,
+		cards:             newSentCards(),
+		cardsMode:         cfg.ModerationCards,
+		miniAppUrl:        cfg.MiniAppUrl,
+		syncInterval:      cfg.SyncInterval,
+		client:            client,
+		logger:            logger,
 	}, nil
 }
 
 func (b *Bot) Setup() {
+	b.registerCommands()
+
 	disp := b.client.Dispatcher
 
 	disp.AddHandler(handlers.NewChatMemberUpdated(nil, b.handleGroup))
 
 	disp.AddHandler(handlers.NewCommand("start", b.handleStart))
+	disp.AddHandler(handlers.NewCommand("help", b.handleHelp))
+	disp.AddHandler(handlers.NewCommand("playlist", b.handlePlaylist))
+	disp.AddHandler(handlers.NewCommand("pending", b.handlePending))
+	disp.AddHandler(handlers.NewCommand("top", b.handleTop))
+	disp.AddHandler(handlers.NewCommand("mine", b.handleMine))
+	disp.AddHandler(handlers.NewCommand("sync", b.handleSync))
+	disp.AddHandler(handlers.NewCommand("lang", b.handleLang))
+	disp.AddHandler(handlers.NewCommand("newplaylist", b.handleNewPlaylist))
+	disp.AddHandler(handlers.NewCommand("mute", b.handleMute))
+	disp.AddHandler(handlers.NewCommand("unmute", b.handleUnmute))
+
+	disp.AddHandler(handlers.NewInlineQuery(nil, b.handleInline))
+	disp.AddHandler(handlers.NewMessage(b.isInlineSent, b.handleInlineSent))
+
+	disp.AddHandler(handlers.NewCallbackQuery(filters.CallbackQuery.Prefix(cardPrefix), b.handleCard))
 
 	disp.AddHandler(handlers.NewMessage(func(msg *types.Message) bool {
-		_, okTitle := msg.Action.(*tg.MessageActionChatEditTitle)
-		// _, okPhoto := msg.Action.(*tg.MessageActionChatEditPhoto)
-		return okTitle // || okPhoto
+		switch msg.Action.(type) {
+		case *tg.MessageActionChatEditTitle, *tg.MessageActionChatEditPhoto, *tg.MessageActionChatDeletePhoto,
+			*tg.MessageActionChatMigrateTo, *tg.MessageActionChannelMigrateFrom, *tg.MessageActionTopicEdit:
+			return true
+		}
+		return false
 	}, b.handleChatAction))
 }
 
 func (b *Bot) Start() error {
+	go b.listenSubmissions()
+
+	if b.syncInterval > 0 {
+		go b.reconcile()
+	}
+
 	return b.client.Idle()
 }
 
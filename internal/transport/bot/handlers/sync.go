@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"backend/internal/infra/queries"
+	"backend/internal/transport/bot/models"
+	"backend/internal/transport/bot/utils"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/celestix/gotgproto/ext"
+)
+
+// reconcile - раз в syncInterval сверять участников всех групп с плейлистами.
+// Ловит изменения, пропущенные, пока бот был выключен (кики, назначения админов)
+func (b *Bot) reconcile() {
+	ticker := time.NewTicker(b.syncInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := b.client.CreateContext()
+
+		groups, err := b.playlistService.Groups(ctx)
+		if err != nil {
+			b.logger.Error("reconcile: " + err.Error())
+			continue
+		}
+
+		for playlistId, chatId := range groups {
+			if _, err := b.syncGroup(ctx, playlistId, chatId); err != nil {
+				b.logger.Warn("reconcile: playlistID: " + playlistId + ", chatID: " + strconv.FormatInt(chatId, 10) + ": " + err.Error())
+			}
+		}
+	}
+}
+
+// syncGroup - перечитать участников группы из Telegram и применить разницу к плейлисту
+func (b *Bot) syncGroup(ctx context.Context, playlistId string, chatId int64) (models.SyncDiff, error) {
+	chat, err := utils.GetChatInfo(b.client, ctx, chatId, 0)
+	if err != nil {
+		return models.SyncDiff{}, err
+	}
+
+	if !chat.Complete {
+		b.logger.Warn("sync: incomplete member list, removals skipped: playlistID: " + playlistId + ", chatID: " + strconv.FormatInt(chatId, 10))
+	}
+
+	diff, err := b.permissionService.Sync(ctx, playlistId, *chat.Users, chat.Complete)
+	if err != nil {
+		return models.SyncDiff{}, err
+	}
+
+	if !diff.Empty() {
+		b.logger.Info("sync: playlistID: " + playlistId + ", chatID: " + strconv.FormatInt(chatId, 10) + ", " + formatDiff(diff))
+	}
+
+	return diff, nil
+}
+
+// handleSync - /sync: сверить участников сейчас. Только для админов группы в Telegram
+func (b *Bot) handleSync(ctx *ext.Context, update *ext.Update) error {
+	playlist, ok := b.groupPlaylist(ctx, update)
+	if !ok || update.EffectiveUser() == nil {
+		return nil
+	}
+
+	chat, ok := b.requireGroupAdmin(ctx, update)
+	if !ok {
+		return nil
+	}
+
+	diff, err := b.permissionService.Sync(ctx, playlist.Id, *chat.Users, chat.Complete)
+	if err != nil {
+		b.replyModerationError(ctx, update, err)
+		return nil
+	}
+
+	b.logger.Info("sync: playlistID: " + playlist.Id + ", chatID: " + strconv.FormatInt(update.EffectiveChat().GetID(), 10) + ", " + formatDiff(diff))
+
+	if diff.Empty() {
+		b.reply(ctx, update, "sync.up_to_date")
+		return nil
+	}
+
+	b.reply(ctx, update, "sync.done", len(diff.Added), len(diff.Edited), len(diff.Removed))
+
+	return nil
+}
+
+// requireGroupAdmin - отправитель команды - админ группы в Telegram. Если нет, бот отвечает сам. Возвращает участников группы
+func (b *Bot) requireGroupAdmin(ctx *ext.Context, update *ext.Update) (*models.Chat, bool) {
+	chat, err := utils.GetChatInfo(b.client, ctx, update.EffectiveChat().GetID(), 0)
+	if err != nil {
+		b.logger.Error(err.Error())
+		b.reply(ctx, update, "sync.failed")
+		return nil, false
+	}
+
+	for _, user := range *chat.Users {
+		if user.UserID == update.EffectiveUser().ID && user.NewRole != queries.PlaylistRoleViewer {
+			return chat, true
+		}
+	}
+
+	b.reply(ctx, update, "sync.admins_only")
+
+	return nil, false
+}
+
+func formatDiff(diff models.SyncDiff) string {
+	result := "added:"
+	for _, user := range diff.Added {
+		result += " " + strconv.FormatInt(user.UserID, 10) + "(" + string(user.NewRole) + ")"
+	}
+
+	result += ", edited:"
+	for _, user := range diff.Edited {
+		result += " " + strconv.FormatInt(user.UserID, 10) + "(" + string(user.PrevRole) + "->" + string(user.NewRole) + ")"
+	}
+
+	result += ", removed:"
+	for _, user := range diff.Removed {
+		result += " " + strconv.FormatInt(user.UserID, 10) + "(" + string(user.PrevRole) + ")"
+	}
+
+	return result
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"backend/internal/infra/queries"
+	"backend/internal/transport/bot/utils"
+	"errors"
+	"strconv"
+
+	"github.com/celestix/gotgproto/ext"
+	"github.com/jackc/pgx/v5"
+)
+
+// topic - тема форума, из которой пришло сообщение, 0 - вся группа
+func (b *Bot) topic(update *ext.Update) int {
+	if update.EffectiveMessage == nil || update.EffectiveMessage.Message == nil {
+		return 0
+	}
+
+	return utils.TopicID(update.EffectiveMessage.ReplyTo)
+}
+
+/*
+handleNewPlaylist - /newplaylist в теме форума: создать отдельный плейлист для этой темы.
+Участники те же, что у группы, язык - как у плейлиста группы. Только для админов группы в Telegram
+*/
+func (b *Bot) handleNewPlaylist(ctx *ext.Context, update *ext.Update) error {
+	if update.EffectiveUser() == nil {
+		return nil
+	}
+
+	topicID := b.topic(update)
+	if topicID == 0 {
+		b.reply(ctx, update, "topic.outside")
+		return nil
+	}
+
+	chatID := update.EffectiveChat().GetID()
+
+	existing, err := b.playlistService.GetByGroup(ctx, chatID, topicID)
+	if err == nil && existing.TopicId == topicID {
+		b.reply(ctx, update, "topic.exists", existing.Title)
+		return nil
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		b.logger.Error(err.Error())
+		b.reply(ctx, update, "common.failed")
+		return nil
+	}
+
+	chat, ok := b.requireGroupAdmin(ctx, update)
+	if !ok {
+		return nil
+	}
+
+	title, err := utils.GetTopicTitle(b.client, ctx, chatID, topicID)
+	if err != nil {
+		b.logger.Warn("failed to get topic title: " + err.Error())
+		title = chat.Title
+	}
+
+	// язык берётся до создания: пока это язык плейлиста группы или отправителя
+	language := b.lang(ctx, update)
+
+	// TODO: set type
+
+	playlist, err := b.playlistService.Create(ctx, title, queries.PlaylistTypeYoutube, chatID, topicID)
+	if err != nil {
+		b.logger.Error(err.Error())
+		b.reply(ctx, update, "common.failed")
+		return nil
+	}
+
+	if err := b.permissionService.AddGroup(ctx, playlist.Id, *chat.Users); err != nil {
+		b.logger.Error(err.Error())
+		b.reply(ctx, update, "common.failed")
+		return nil
+	}
+
+	if err := b.playlistService.SetLanguage(ctx, playlist.Id, language); err != nil {
+		b.logger.Error(err.Error())
+	}
+
+	b.logger.Info("topic playlist created: playlistID: " + playlist.Id + ", chatID: " + strconv.FormatInt(chatID, 10) + ", topicID: " + strconv.Itoa(topicID))
+
+	b.reply(ctx, update, "topic.created", title)
+
+	return nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"backend/internal/infra/queries"
+	"backend/internal/transport/api/dto"
 
 	"github.com/gotd/td/tg"
 )
@@ -12,10 +13,24 @@ type ParticipantData struct {
 	UserID   int64
 	ChatID   int64
 	ActorID  int64
+	Profile  dto.TelegramData // пустой, если Telegram не прислал данные юзера
 }
 
 type Chat struct {
-	Title string
-	Photo tg.ChatPhotoClass
-	Users *[]ParticipantData
+	Title        string
+	Photo        tg.ChatPhotoClass
+	Users        *[]ParticipantData
+	Complete     bool  // Users - все участники группы. В супергруппе Telegram может вернуть не всех
+	MigratedFrom int64 // ID обычной группы, из которой сделана эта супергруппа, 0 - не было миграции
+}
+
+// SyncDiff - изменения участников плейлиста после сверки с Telegram
+type SyncDiff struct {
+	Added   []ParticipantData
+	Edited  []ParticipantData // PrevRole - роль до сверки
+	Removed []ParticipantData
+}
+
+func (d SyncDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Edited) == 0 && len(d.Removed) == 0
 }
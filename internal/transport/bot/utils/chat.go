@@ -3,11 +3,13 @@ package utils
 import (
 	"backend/internal/infra/queries"
 	"backend/internal/transport/bot/models"
+	"bytes"
 	"context"
 	"errors"
 
 	"github.com/celestix/gotgproto"
 	"github.com/celestix/gotgproto/functions"
+	"github.com/gotd/td/telegram/downloader"
 	"github.com/gotd/td/tg"
 )
 
@@ -23,6 +25,9 @@ func GetChatInfo(client *gotgproto.Client, ctx context.Context, chatID, actorID
 	var chat tg.ChatClass
 	var info *models.Chat
 	var users *[]models.ParticipantData
+	var migratedFrom int64
+	var participantsCount int
+	var countKnown bool
 
 	switch peerResult := peer.(type) {
 	case *tg.InputPeerChat:
@@ -36,6 +41,7 @@ func GetChatInfo(client *gotgproto.Client, ctx context.Context, chatID, actorID
 		}
 
 		chat = chatFull.Chats[0]
+		roles := chatRoles(chatFull.FullChat)
 		var usersTemp []models.ParticipantData
 		for _, user := range chatFull.Users {
 			if val, ok := user.(*tg.User); ok {
@@ -43,19 +49,23 @@ func GetChatInfo(client *gotgproto.Client, ctx context.Context, chatID, actorID
 					continue
 				}
 
-				if val.ID != actorID {
-					usersTemp = append(usersTemp, models.ParticipantData{
-						NewRole: queries.PlaylistRoleViewer,
-						ChatID:  chatID,
-						UserID:  val.ID,
-					})
-				} else {
-					usersTemp = append(usersTemp, models.ParticipantData{
-						NewRole: queries.PlaylistRoleOwner,
-						ChatID:  chatID,
-						UserID:  val.ID,
-					})
+				role, ok := roles[val.ID]
+				if !ok {
+					role = queries.PlaylistRoleViewer
 				}
+				// добавивший бота становится владельцем вместо создателя группы, actorID == 0 - роли как в Telegram
+				if actorID != 0 && val.ID == actorID {
+					role = queries.PlaylistRoleOwner
+				} else if actorID != 0 && role == queries.PlaylistRoleOwner {
+					role = queries.PlaylistRoleModerator
+				}
+
+				usersTemp = append(usersTemp, models.ParticipantData{
+					NewRole: role,
+					ChatID:  chatID,
+					UserID:  val.ID,
+					Profile: Profile(val),
+				})
 			}
 		}
 		users = &usersTemp
@@ -73,6 +83,10 @@ func GetChatInfo(client *gotgproto.Client, ctx context.Context, chatID, actorID
 		}
 
 		chat = channelFull.Chats[0]
+		if full, ok := channelFull.FullChat.(*tg.ChannelFull); ok {
+			migratedFrom = full.MigratedFromChatID
+			participantsCount, countKnown = full.GetParticipantsCount()
+		}
 	default:
 		return nil, errors.New("unknown peer type " + peerResult.TypeName())
 	}
@@ -84,19 +98,22 @@ func GetChatInfo(client *gotgproto.Client, ctx context.Context, chatID, actorID
 	switch c := chat.(type) {
 	case *tg.Chat:
 		info = &models.Chat{
-			Title: c.Title,
-			Photo: c.Photo,
+			Title:    c.Title,
+			Photo:    c.Photo,
+			Complete: true,
 		}
 		if users == nil || len(*users) == 0 {
 			return info, errors.New("no chat users found")
 		}
 	case *tg.Channel:
 		info = &models.Chat{
-			Title: c.Title,
-			Photo: c.Photo,
+			Title:        c.Title,
+			Photo:        c.Photo,
+			MigratedFrom: migratedFrom,
 		}
 
-		users, err = iterateParticipants(client, ctx, &tg.InputChannel{
+		var fetched int
+		users, fetched, err = iterateParticipants(client, ctx, &tg.InputChannel{
 			AccessHash: c.AccessHash,
 			ChannelID:  c.ID,
 		})
@@ -104,6 +121,9 @@ func GetChatInfo(client *gotgproto.Client, ctx context.Context, chatID, actorID
 		if err != nil {
 			return nil, err
 		}
+
+		// без числа участников полноту списка проверить нельзя
+		info.Complete = countKnown && fetched >= participantsCount
 	default:
 		return nil, errors.New("unknown channel type " + c.TypeName())
 	}
@@ -112,3 +132,72 @@ func GetChatInfo(client *gotgproto.Client, ctx context.Context, chatID, actorID
 
 	return info, nil
 }
+
+// MigratedTo - ID супергруппы, в которую превратили обычную группу, 0 - группа не мигрировала
+func MigratedTo(client *gotgproto.Client, ctx context.Context, chatID int64) (int64, error) {
+	resp, err := client.API().MessagesGetChats(ctx, []int64{chatID})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, chat := range resp.GetChats() {
+		c, ok := chat.(*tg.Chat)
+		if !ok || c.ID != chatID || c.MigratedTo == nil {
+			continue
+		}
+
+		if channel, ok := c.MigratedTo.AsNotEmpty(); ok {
+			return channel.GetChannelID(), nil
+		}
+	}
+
+	return 0, nil
+}
+
+// chatRoles - роли участников обычной группы: создатель - владелец, админы - модераторы
+func chatRoles(full tg.ChatFullClass) map[int64]queries.PlaylistRole {
+	roles := make(map[int64]queries.PlaylistRole)
+
+	chatFull, ok := full.(*tg.ChatFull)
+	if !ok {
+		return roles
+	}
+
+	participants, ok := chatFull.Participants.(*tg.ChatParticipants)
+	if !ok {
+		return roles
+	}
+
+	for _, participant := range participants.Participants {
+		switch p := participant.(type) {
+		case *tg.ChatParticipantCreator:
+			roles[p.UserID] = queries.PlaylistRoleOwner
+		case *tg.ChatParticipantAdmin:
+			roles[p.UserID] = queries.PlaylistRoleModerator
+		case *tg.ChatParticipant:
+			roles[p.UserID] = queries.PlaylistRoleViewer
+		}
+	}
+
+	return roles
+}
+
+// DownloadChatPhoto - скачать текущее фото группы в большом размере. photoID - ID фото из ChatPhoto или MessageActionChatEditPhoto
+func DownloadChatPhoto(client *gotgproto.Client, ctx context.Context, chatID, photoID int64) ([]byte, error) {
+	peer := functions.GetInputPeerClassFromId(client.PeerStorage, chatID)
+	if peer == nil {
+		return nil, errors.New("peer is nil")
+	}
+
+	var buf bytes.Buffer
+	_, err := downloader.NewDownloader().Download(client.API(), &tg.InputPeerPhotoFileLocation{
+		Big:     true,
+		Peer:    peer,
+		PhotoID: photoID,
+	}).Stream(ctx, &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -11,12 +11,16 @@ import (
 	"github.com/gotd/td/tg"
 )
 
-func iterateParticipants(client *gotgproto.Client, ctx context.Context, channel *tg.InputChannel) (*[]models.ParticipantData, error) {
+/*
+iterateParticipants - участники супергруппы: недавние и отдельно админы. Второе значение - сколько участников
+вернул Telegram в списке недавних, он может отдать не всех
+*/
+func iterateParticipants(client *gotgproto.Client, ctx context.Context, channel *tg.InputChannel) (*[]models.ParticipantData, int, error) {
 	const limit = 100
 	offset := 0
 	var data []models.ParticipantData
 
-	for offset%100 == 0 {
+	for {
 		resp, err := client.API().ChannelsGetParticipants(ctx, &tg.ChannelsGetParticipantsRequest{
 			Channel: channel,
 			Filter:  &tg.ChannelParticipantsRecent{},
@@ -25,13 +29,14 @@ func iterateParticipants(client *gotgproto.Client, ctx context.Context, channel
 			Hash:    0,
 		})
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		val, ok := resp.(*tg.ChannelsChannelParticipants)
 		if !ok {
-			return nil, errors.New("invalid response " + resp.TypeName())
+			return nil, 0, errors.New("invalid response " + resp.TypeName())
 		}
+		users := val.MapUsers().UserToMap()
 
 		for _, participant := range val.Participants {
 			var userId int64
@@ -59,14 +64,22 @@ func iterateParticipants(client *gotgproto.Client, ctx context.Context, channel
 				UserID:  userId,
 				NewRole: role,
 				ChatID:  channel.ChannelID,
+				Profile: Profile(users[userId]),
 			})
 		}
 
 		offset += len(val.Participants)
+
+		// неполная или пустая страница - последняя
+		if len(val.Participants) < limit {
+			break
+		}
 	}
 
+	fetched := offset
+
 	offset = 0
-	for offset%100 == 0 {
+	for {
 		resp, err := client.API().ChannelsGetParticipants(ctx, &tg.ChannelsGetParticipantsRequest{
 			Channel: channel,
 			Filter:  &tg.ChannelParticipantsAdmins{},
@@ -75,13 +88,14 @@ func iterateParticipants(client *gotgproto.Client, ctx context.Context, channel
 			Hash:    0,
 		})
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		val, ok := resp.(*tg.ChannelsChannelParticipants)
 		if !ok {
-			return nil, errors.New("invalid response " + resp.TypeName())
+			return nil, 0, errors.New("invalid response " + resp.TypeName())
 		}
+		users := val.MapUsers().UserToMap()
 
 		for _, participant := range val.Participants {
 			var userId int64
@@ -105,10 +119,8 @@ func iterateParticipants(client *gotgproto.Client, ctx context.Context, channel
 				continue
 			}
 
-			if slices.Contains(data, models.ParticipantData{
-				UserID:  userId,
-				NewRole: role,
-				ChatID:  channel.ChannelID,
+			if slices.ContainsFunc(data, func(p models.ParticipantData) bool {
+				return p.UserID == userId && p.NewRole == role
 			}) {
 				continue
 			}
@@ -117,11 +129,17 @@ func iterateParticipants(client *gotgproto.Client, ctx context.Context, channel
 				UserID:  userId,
 				NewRole: role,
 				ChatID:  channel.ChannelID,
+				Profile: Profile(users[userId]),
 			})
 		}
 
 		offset += len(val.Participants)
+
+		// неполная или пустая страница - последняя
+		if len(val.Participants) < limit {
+			break
+		}
 	}
 
-	return &data, nil
+	return &data, fetched, nil
 }
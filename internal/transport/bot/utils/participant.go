@@ -19,5 +19,7 @@ func HandleParticipant(update *ext.Update) (models.ParticipantData, error) {
 		return data, errors.New("invalid update type " + u.TypeName())
 	}
 
+	data.Profile = UpdateProfile(update, data.UserID)
+
 	return data, nil
 }
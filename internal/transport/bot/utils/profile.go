@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"backend/internal/transport/api/dto"
+
+	"github.com/celestix/gotgproto/ext"
+	"github.com/gotd/td/tg"
+)
+
+// Profile - профиль юзера из данных Telegram
+func Profile(user *tg.User) dto.TelegramData {
+	if user == nil {
+		return dto.TelegramData{}
+	}
+
+	return dto.TelegramData{
+		ID:           user.ID,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		Username:     user.Username,
+		LanguageCode: user.LangCode,
+	}
+}
+
+// UpdateProfile - профиль юзера из сущностей апдейта
+func UpdateProfile(update *ext.Update, userId int64) dto.TelegramData {
+	if update.Entities == nil {
+		return dto.TelegramData{}
+	}
+
+	return Profile(update.Entities.Users[userId])
+}
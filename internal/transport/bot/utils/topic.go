@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"errors"
+
+	"github.com/celestix/gotgproto"
+	"github.com/celestix/gotgproto/functions"
+	"github.com/gotd/td/tg"
+)
+
+/*
+TopicID - тема форума, в которой написано сообщение, по его заголовку ответа.
+0 - сообщение не в теме: обычная группа или тема General.
+ID темы - это ID сервисного сообщения, которым её создали
+*/
+func TopicID(header tg.MessageReplyHeaderClass) int {
+	reply, ok := header.(*tg.MessageReplyHeader)
+	if !ok || !reply.ForumTopic {
+		return 0
+	}
+
+	// ответ на сообщение внутри темы, иначе само сообщение ссылается на начало темы
+	if topicID, ok := reply.GetReplyToTopID(); ok {
+		return topicID
+	}
+
+	return reply.ReplyToMsgID
+}
+
+// TopicReplyTo - куда отправить сообщение, чтобы оно попало в тему topicID. Для 0 - nil, то есть просто в чат
+func TopicReplyTo(topicID int) tg.InputReplyToClass {
+	if topicID == 0 {
+		return nil
+	}
+
+	return &tg.InputReplyToMessage{ReplyToMsgID: topicID}
+}
+
+// GetTopicTitle - название темы форума в супергруппе chatID
+func GetTopicTitle(client *gotgproto.Client, ctx context.Context, chatID int64, topicID int) (string, error) {
+	peer, ok := functions.GetInputPeerClassFromId(client.PeerStorage, chatID).(*tg.InputPeerChannel)
+	if !ok {
+		return "", errors.New("chat is not a supergroup")
+	}
+
+	resp, err := client.API().ChannelsGetForumTopicsByID(ctx, &tg.ChannelsGetForumTopicsByIDRequest{
+		Channel: &tg.InputChannel{ChannelID: peer.ChannelID, AccessHash: peer.AccessHash},
+		Topics:  []int{topicID},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, topic := range resp.Topics {
+		if t, ok := topic.(*tg.ForumTopic); ok && t.ID == topicID {
+			return t.Title, nil
+		}
+	}
+
+	return "", errors.New("topic not found")
+}
@@ -0,0 +1,33 @@
+package export
+
+import (
+	"backend/internal/transport/api/dto"
+	"backend/pkg/youtube"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+func writeCSV(w io.Writer, playlist dto.Playlist, _ Options) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"id", "title", "authors", "length", "explicit", "url"}); err != nil {
+		return err
+	}
+
+	for _, track := range playlist.Tracks {
+		if err := cw.Write([]string{
+			track.Id,
+			track.Title,
+			track.Authors,
+			strconv.Itoa(int(track.Length)),
+			strconv.FormatBool(track.Explicit),
+			youtube.TrackURL(track.Id),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
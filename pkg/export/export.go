@@ -0,0 +1,77 @@
+package export
+
+import (
+	"backend/internal/transport/api/dto"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+type Format struct {
+	ContentType string
+	Extension   string
+	Write       func(w io.Writer, playlist dto.Playlist, opts Options) error
+}
+
+type Options struct {
+	// PathTemplate - где у диджея лежат аудиофайлы, для форматов DJ софта.
+	// Подстановки: {index}, {id}, {title}, {authors}
+	// Пример - "C:/Music/{authors} - {title}.mp3"
+	PathTemplate string
+}
+
+var formats = map[string]Format{
+	"m3u8":      {ContentType: "audio/x-mpegurl; charset=utf-8", Extension: "m3u8", Write: writeM3U},
+	"xspf":      {ContentType: "application/xspf+xml; charset=utf-8", Extension: "xspf", Write: writeXSPF},
+	"csv":       {ContentType: "text/csv; charset=utf-8", Extension: "csv", Write: writeCSV},
+	"json":      {ContentType: "application/json; charset=utf-8", Extension: "json", Write: writeJSON},
+	"rekordbox": {ContentType: "application/xml; charset=utf-8", Extension: "xml", Write: writeRekordbox},
+	"traktor":   {ContentType: "application/xml; charset=utf-8", Extension: "nml", Write: writeTraktor},
+}
+
+// Get - получить формат экспорта по названию
+func Get(name string) (Format, bool) {
+	format, ok := formats[name]
+	return format, ok
+}
+
+// Disposition - заголовок Content-Disposition для файла с названием плейлиста
+func (f Format) Disposition(title string) string {
+	name := safeName(title)
+	if name == "" {
+		name = "playlist"
+	}
+
+	// ascii fallback для старых клиентов, полное название - в filename*
+	fallback := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII {
+			return '_'
+		}
+		return r
+	}, name)
+
+	return `attachment; filename="` + fallback + "." + f.Extension + `"; filename*=UTF-8''` + url.PathEscape(name+"."+f.Extension)
+}
+
+// path - локальный путь до файла трека по шаблону, всегда через "/"
+func (o Options) path(index int, track dto.Track) string {
+	return strings.NewReplacer(
+		"\\", "/",
+		"{index}", fmt.Sprintf("%03d", index),
+		"{id}", track.Id,
+		"{title}", safeName(track.Title),
+		"{authors}", safeName(track.Authors),
+	).Replace(o.PathTemplate)
+}
+
+// safeName - убрать символы, недопустимые в именах файлов
+func safeName(s string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return -1
+		}
+		return r
+	}, s))
+}
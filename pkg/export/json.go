@@ -0,0 +1,58 @@
+package export
+
+import (
+	"backend/internal/transport/api/dto"
+	"backend/pkg/youtube"
+	"io"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// jsonVersion - версия JSON документа, увеличивать при несовместимых изменениях
+const jsonVersion = 1
+
+type jsonDocument struct {
+	Version    int          `json:"version"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Playlist   jsonPlaylist `json:"playlist"`
+	Tracks     []jsonTrack  `json:"tracks"`
+}
+
+type jsonPlaylist struct {
+	Id     string `json:"id"`
+	Title  string `json:"title"`
+	Count  int    `json:"count"`
+	Length int    `json:"length"`
+}
+
+type jsonTrack struct {
+	dto.Track
+	Url string `json:"url"`
+}
+
+func writeJSON(w io.Writer, playlist dto.Playlist, _ Options) error {
+	doc := jsonDocument{
+		Version:    jsonVersion,
+		ExportedAt: time.Now().UTC(),
+		Playlist: jsonPlaylist{
+			Id:    playlist.Id,
+			Title: playlist.Title,
+			Count: len(playlist.Tracks),
+		},
+		Tracks: make([]jsonTrack, len(playlist.Tracks)),
+	}
+
+	for i, track := range playlist.Tracks {
+		doc.Tracks[i] = jsonTrack{Track: track, Url: youtube.TrackURL(track.Id)}
+		doc.Playlist.Length += int(track.Length)
+	}
+
+	data, err := sonic.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
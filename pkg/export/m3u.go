@@ -0,0 +1,30 @@
+package export
+
+import (
+	"backend/internal/transport/api/dto"
+	"backend/pkg/youtube"
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+func writeM3U(w io.Writer, playlist dto.Playlist, _ Options) error {
+	bw := bufio.NewWriter(w)
+
+	if _, err := bw.WriteString("#EXTM3U\n#PLAYLIST:" + oneLine(playlist.Title) + "\n"); err != nil {
+		return err
+	}
+
+	for _, track := range playlist.Tracks {
+		if _, err := bw.WriteString("#EXTINF:" + strconv.Itoa(int(track.Length)) + "," + oneLine(track.Authors) + " - " + oneLine(track.Title) + "\n" + youtube.TrackURL(track.Id) + "\n"); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
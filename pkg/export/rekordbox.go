@@ -0,0 +1,130 @@
+package export
+
+import (
+	"backend/internal/transport/api/dto"
+	"backend/pkg/youtube"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+type rekordboxDocument struct {
+	XMLName    xml.Name            `xml:"DJ_PLAYLISTS"`
+	Version    string              `xml:"Version,attr"`
+	Product    rekordboxProduct    `xml:"PRODUCT"`
+	Collection rekordboxCollection `xml:"COLLECTION"`
+	Playlists  rekordboxNode       `xml:"PLAYLISTS>NODE"`
+}
+
+type rekordboxProduct struct {
+	Name    string `xml:"Name,attr"`
+	Version string `xml:"Version,attr"`
+	Company string `xml:"Company,attr"`
+}
+
+type rekordboxCollection struct {
+	Entries int              `xml:"Entries,attr"`
+	Tracks  []rekordboxTrack `xml:"TRACK"`
+}
+
+type rekordboxTrack struct {
+	TrackID   int    `xml:"TrackID,attr"`
+	Name      string `xml:"Name,attr"`
+	Artist    string `xml:"Artist,attr"`
+	Kind      string `xml:"Kind,attr,omitempty"`
+	TotalTime int32  `xml:"TotalTime,attr"`
+	Comments  string `xml:"Comments,attr"`
+	Location  string `xml:"Location,attr"`
+}
+
+type rekordboxNode struct {
+	Type    int                 `xml:"Type,attr"`
+	Name    string              `xml:"Name,attr"`
+	Count   *int                `xml:"Count,attr,omitempty"`
+	KeyType *int                `xml:"KeyType,attr,omitempty"`
+	Entries *int                `xml:"Entries,attr,omitempty"`
+	Nodes   []rekordboxNode     `xml:"NODE"`
+	Tracks  []rekordboxTrackKey `xml:"TRACK"`
+}
+
+type rekordboxTrackKey struct {
+	Key int `xml:"Key,attr"`
+}
+
+// writeRekordbox - rekordbox collection XML (File > Import > rekordbox xml)
+func writeRekordbox(w io.Writer, playlist dto.Playlist, opts Options) error {
+	count := len(playlist.Tracks)
+	one := 1
+	keyType := 0
+
+	doc := rekordboxDocument{
+		Version: "1.0.0",
+		Product: rekordboxProduct{Name: "Muse", Version: "1.0.0", Company: "CringeDrivenDevelopment"},
+		Collection: rekordboxCollection{
+			Entries: count,
+			Tracks:  make([]rekordboxTrack, count),
+		},
+		Playlists: rekordboxNode{
+			Type:  0,
+			Name:  "ROOT",
+			Count: &one,
+			Nodes: []rekordboxNode{{
+				Type:    1,
+				Name:    playlist.Title,
+				KeyType: &keyType,
+				Entries: &count,
+				Tracks:  make([]rekordboxTrackKey, count),
+			}},
+		},
+	}
+
+	for i, track := range playlist.Tracks {
+		location := opts.path(i+1, track)
+
+		doc.Collection.Tracks[i] = rekordboxTrack{
+			TrackID:   i + 1,
+			Name:      track.Title,
+			Artist:    track.Authors,
+			Kind:      rekordboxKind(location),
+			TotalTime: track.Length,
+			Comments:  youtube.TrackURL(track.Id),
+			Location: (&url.URL{
+				Scheme: "file",
+				Host:   "localhost",
+				Path:   "/" + strings.TrimPrefix(location, "/"),
+			}).String(),
+		}
+		doc.Playlists.Nodes[0].Tracks[i] = rekordboxTrackKey{Key: i + 1}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func rekordboxKind(location string) string {
+	switch strings.ToLower(path.Ext(location)) {
+	case ".mp3":
+		return "MP3 File"
+	case ".m4a":
+		return "M4A File"
+	case ".wav":
+		return "WAV File"
+	case ".aiff", ".aif":
+		return "AIFF File"
+	case ".flac":
+		return "FLAC File"
+	default:
+		return ""
+	}
+}
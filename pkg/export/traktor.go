@@ -0,0 +1,167 @@
+package export
+
+import (
+	"backend/internal/transport/api/dto"
+	"backend/pkg/youtube"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// traktorDefaultVolume - системный диск macOS, если в шаблоне нет ни буквы диска, ни /Volumes/<name>
+const traktorDefaultVolume = "Macintosh HD"
+
+type traktorDocument struct {
+	XMLName    xml.Name          `xml:"NML"`
+	Version    string            `xml:"VERSION,attr"`
+	Head       traktorHead       `xml:"HEAD"`
+	Collection traktorCollection `xml:"COLLECTION"`
+	Playlists  traktorFolder     `xml:"PLAYLISTS>NODE"`
+}
+
+type traktorHead struct {
+	Company string `xml:"COMPANY,attr"`
+	Program string `xml:"PROGRAM,attr"`
+}
+
+type traktorCollection struct {
+	Entries int            `xml:"ENTRIES,attr"`
+	Tracks  []traktorEntry `xml:"ENTRY"`
+}
+
+type traktorEntry struct {
+	Title    string          `xml:"TITLE,attr"`
+	Artist   string          `xml:"ARTIST,attr"`
+	Location traktorLocation `xml:"LOCATION"`
+	Info     traktorInfo     `xml:"INFO"`
+}
+
+type traktorLocation struct {
+	Dir    string `xml:"DIR,attr"`
+	File   string `xml:"FILE,attr"`
+	Volume string `xml:"VOLUME,attr"`
+}
+
+type traktorInfo struct {
+	Playtime int32  `xml:"PLAYTIME,attr"`
+	Comment  string `xml:"COMMENT,attr"`
+}
+
+type traktorFolder struct {
+	Type     string          `xml:"TYPE,attr"`
+	Name     string          `xml:"NAME,attr"`
+	Subnodes traktorSubnodes `xml:"SUBNODES"`
+}
+
+type traktorSubnodes struct {
+	Count int           `xml:"COUNT,attr"`
+	Nodes []traktorNode `xml:"NODE"`
+}
+
+type traktorNode struct {
+	Type     string          `xml:"TYPE,attr"`
+	Name     string          `xml:"NAME,attr"`
+	Playlist traktorPlaylist `xml:"PLAYLIST"`
+}
+
+type traktorPlaylist struct {
+	Entries int                    `xml:"ENTRIES,attr"`
+	Type    string                 `xml:"TYPE,attr"`
+	Keys    []traktorPlaylistEntry `xml:"ENTRY"`
+}
+
+// traktorPlaylistEntry - у каждого трека плейлиста свой ENTRY с одним PRIMARYKEY
+type traktorPlaylistEntry struct {
+	PrimaryKey traktorTrackKey `xml:"PRIMARYKEY"`
+}
+
+type traktorTrackKey struct {
+	Type string `xml:"TYPE,attr"`
+	Key  string `xml:"KEY,attr"`
+}
+
+// writeTraktor - коллекция Traktor NML (импорт через Import Playlist)
+func writeTraktor(w io.Writer, playlist dto.Playlist, opts Options) error {
+	count := len(playlist.Tracks)
+
+	doc := traktorDocument{
+		Version: "19",
+		Head:    traktorHead{Company: "www.native-instruments.com", Program: "Traktor"},
+		Collection: traktorCollection{
+			Entries: count,
+			Tracks:  make([]traktorEntry, count),
+		},
+		Playlists: traktorFolder{
+			Type: "FOLDER",
+			Name: "$ROOT",
+			Subnodes: traktorSubnodes{
+				Count: 1,
+				Nodes: []traktorNode{{
+					Type: "PLAYLIST",
+					Name: playlist.Title,
+					Playlist: traktorPlaylist{
+						Entries: count,
+						Type:    "LIST",
+						Keys:    make([]traktorPlaylistEntry, count),
+					},
+				}},
+			},
+		},
+	}
+
+	for i, track := range playlist.Tracks {
+		location := traktorSplit(opts.path(i+1, track))
+
+		doc.Collection.Tracks[i] = traktorEntry{
+			Title:    track.Title,
+			Artist:   track.Authors,
+			Location: location,
+			Info: traktorInfo{
+				Playtime: track.Length,
+				Comment:  youtube.TrackURL(track.Id),
+			},
+		}
+		doc.Playlists.Subnodes.Nodes[0].Playlist.Keys[i] = traktorPlaylistEntry{
+			PrimaryKey: traktorTrackKey{
+				Type: "TRACK",
+				Key:  location.Volume + location.Dir + location.File,
+			},
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// traktorSplit - разбить путь на диск, папку и файл. Traktor разделяет папки как "/:"
+func traktorSplit(path string) traktorLocation {
+	volume := traktorDefaultVolume
+
+	if len(path) >= 2 && path[1] == ':' {
+		volume, path = path[:2], path[2:]
+	} else if rest, ok := strings.CutPrefix(path, "/Volumes/"); ok {
+		volume, path, _ = strings.Cut(rest, "/")
+		path = "/" + path
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	file := parts[len(parts)-1]
+
+	dir := "/:"
+	for _, part := range parts[:len(parts)-1] {
+		if part != "" {
+			dir += part + "/:"
+		}
+	}
+
+	return traktorLocation{Dir: dir, File: file, Volume: volume}
+}
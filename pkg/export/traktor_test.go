@@ -0,0 +1,77 @@
+package export
+
+import (
+	"backend/internal/transport/api/dto"
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+// структура для разбора результата, независимая от тегов writeTraktor
+type parsedNML struct {
+	Collection struct {
+		Entries []struct {
+			Title string `xml:"TITLE,attr"`
+		} `xml:"ENTRY"`
+	} `xml:"COLLECTION"`
+	Playlists []struct {
+		Name     string `xml:"NAME,attr"`
+		Playlist struct {
+			Entries int `xml:"ENTRIES,attr"`
+			Entry   []struct {
+				Keys []struct {
+					Type string `xml:"TYPE,attr"`
+					Key  string `xml:"KEY,attr"`
+				} `xml:"PRIMARYKEY"`
+			} `xml:"ENTRY"`
+		} `xml:"PLAYLIST"`
+	} `xml:"PLAYLISTS>NODE>SUBNODES>NODE"`
+}
+
+func TestWriteTraktorRoundTrip(t *testing.T) {
+	playlist := dto.Playlist{
+		Title: "Party",
+		Tracks: []dto.Track{
+			{Id: "aaaaaaaaaaa", Title: "One", Authors: "A", Length: 100},
+			{Id: "bbbbbbbbbbb", Title: "Two", Authors: "B", Length: 200},
+			{Id: "ccccccccccc", Title: "Three", Authors: "C", Length: 300},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeTraktor(&buf, playlist, Options{PathTemplate: "C:/Music/{authors} - {title}.mp3"}); err != nil {
+		t.Fatalf("writeTraktor: %v", err)
+	}
+
+	var doc parsedNML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+
+	if got := len(doc.Collection.Entries); got != len(playlist.Tracks) {
+		t.Fatalf("collection entries = %d, want %d", got, len(playlist.Tracks))
+	}
+	if len(doc.Playlists) != 1 {
+		t.Fatalf("playlists = %d, want 1", len(doc.Playlists))
+	}
+
+	list := doc.Playlists[0].Playlist
+	if got := len(list.Entry); got != len(playlist.Tracks) {
+		t.Fatalf("playlist ENTRY elements = %d, want %d\n%s", got, len(playlist.Tracks), buf.String())
+	}
+	if list.Entries != len(playlist.Tracks) {
+		t.Errorf("ENTRIES attr = %d, want %d", list.Entries, len(playlist.Tracks))
+	}
+
+	for i, entry := range list.Entry {
+		if len(entry.Keys) != 1 {
+			t.Fatalf("entry %d has %d PRIMARYKEY, want 1", i, len(entry.Keys))
+		}
+
+		track := playlist.Tracks[i]
+		want := "C:/:Music/:" + track.Authors + " - " + track.Title + ".mp3"
+		if entry.Keys[0].Type != "TRACK" || entry.Keys[0].Key != want {
+			t.Errorf("entry %d key = %s %q, want TRACK %q", i, entry.Keys[0].Type, entry.Keys[0].Key, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"backend/internal/transport/api/dto"
+	"backend/pkg/youtube"
+	"encoding/xml"
+	"io"
+)
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Version string      `xml:"version,attr"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location   string `xml:"location"`
+	Identifier string `xml:"identifier"`
+	Title      string `xml:"title"`
+	Creator    string `xml:"creator"`
+	Image      string `xml:"image,omitempty"`
+	Duration   int64  `xml:"duration"` // миллисекунды
+}
+
+func writeXSPF(w io.Writer, playlist dto.Playlist, _ Options) error {
+	doc := xspfPlaylist{
+		Version: "1",
+		Xmlns:   "http://xspf.org/ns/0/",
+		Title:   playlist.Title,
+		Tracks:  make([]xspfTrack, len(playlist.Tracks)),
+	}
+
+	for i, track := range playlist.Tracks {
+		doc.Tracks[i] = xspfTrack{
+			Location:   youtube.TrackURL(track.Id),
+			Identifier: track.Id,
+			Title:      track.Title,
+			Creator:    track.Authors,
+			Image:      track.Thumbnail,
+			Duration:   int64(track.Length) * 1000,
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
@@ -0,0 +1,95 @@
+package i18n
+
+var en = map[string]string{
+	"language.name": "English",
+
+	"start.text": "Hi, I'm Lottie! A bot for moderating playlists.\n\n" +
+		"Add me to a group and I'll load its members. If you want a private playlist just for you, open the mini app.\n\n" +
+		"To suggest a track right from a group, type @%s and the track name.\n\n" +
+		"To manage the playlists you have access to, open the mini app)",
+
+	"group.welcome": "Hi, I'm Lottie - a bot for managing playlists!\n\n" +
+		"You can make me an admin (so I can see the recent actions log and all members and admins)",
+	"group.admin_thanks": "Thanks for the admin rights, creating the playlist now!",
+
+	"common.no_playlist":      "This group has no playlist",
+	"common.not_enough_perms": "Not enough permissions",
+	"common.failed":           "Something went wrong",
+	"common.done":             "Done",
+
+	"cmd.start":    "About the bot",
+	"cmd.help":     "List of commands",
+	"cmd.playlist": "Group playlist",
+	"cmd.pending":  "Tracks awaiting moderation",
+	"cmd.top":      "Most frequent artists",
+	"cmd.mine":     "My suggested tracks",
+	"cmd.mute":     "Forbid suggesting tracks (reply to a message)",
+	"cmd.unmute":   "Lift the ban (reply to a message)",
+	"cmd.sync":     "Sync playlist members with the group",
+	"cmd.lang":     "Bot language in the group",
+
+	"cmd.newplaylist": "Separate playlist for this topic",
+
+	"playlist.info": "«%s»\n\nTracks: %d, approved: %d\nApproved duration: %s",
+	"playlist.open": "Open playlist",
+
+	"pending.empty": "No tracks awaiting moderation",
+	"pending.list":  "Awaiting moderation: %d\n\n%s",
+
+	"top.empty": "The playlist has no tracks yet",
+	"top.list":  "Most frequent artists:\n\n%s",
+
+	"mine.empty":      "You haven't suggested any tracks yet",
+	"mine.list":       "Your latest tracks:\n\n%s",
+	"status.pending":  "awaiting moderation",
+	"status.approved": "approved",
+	"status.declined": "declined",
+
+	"help.text": "Commands in a group with a playlist:\n\n%s\n\n" +
+		"To suggest a track, type @%s and the track name or open the mini app",
+
+	"inline.submitted":  "Track suggested to the playlist",
+	"inline.muted":      "You are not allowed to suggest tracks to this playlist",
+	"inline.not_member": "You are not a member of this group's playlist",
+
+	"mute.done":                   "Done, they can no longer suggest tracks",
+	"mute.done_for":               "Done, they can no longer suggest tracks (for %s)",
+	"unmute.done":                 "Done, they can suggest tracks again",
+	"moderation.reply_needed":     "Reply with the command to a member's message",
+	"moderation.member_not_found": "Member not found in the playlist",
+	"moderation.invalid":          "Not allowed: %s",
+
+	"sync.failed":      "Failed to get group members",
+	"sync.admins_only": "This command is for group admins only",
+	"sync.up_to_date":  "Members already match the group",
+	"sync.done":        "Done: %d added, %d roles changed, %d removed",
+
+	"card.text":             "New track in «%s»\n\n%s - %s\n%s\n\nSuggested by: %s",
+	"card.approve":          "Approve",
+	"card.decline":          "Decline",
+	"card.approved":         "Approved by",
+	"card.declined":         "Declined by",
+	"card.outdated":         "Outdated card",
+	"card.already_reviewed": "The track is already reviewed or you have no access to the playlist",
+
+	"lang.current": "Bot language in this group: %s\n\nChange: /lang <code>, available: %s",
+	"lang.changed": "Done, I speak English now",
+	"lang.unknown": "Unknown language, available: %s",
+
+	"topic.outside": "This command only works inside a forum topic",
+	"topic.exists":  "This topic already has the playlist «%s»",
+	"topic.created": "Done, this topic now has its own playlist «%s». Tracks suggested here will go to it",
+
+	"error.not_found":         "entry not found",
+	"error.not_enough_perms":  "not enough permissions",
+	"error.muted":             "muted in this playlist",
+	"error.invalid_token":     "invalid token",
+	"error.invalid_init_data": "invalid init data",
+	"error.invalid_login":     "invalid login widget data",
+	"error.invalid_input":     "invalid input",
+	"error.internal":          "internal server error",
+	"error.unauthorized":      "unauthorized",
+	"error.admin_only":        "admin only",
+	"error.token_playlist":    "token is not allowed for this playlist",
+	"error.validation":        "validation failed",
+}
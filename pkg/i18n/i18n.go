@@ -0,0 +1,125 @@
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default - язык, если язык чата или юзера не поддерживается
+const Default = "ru"
+
+/*
+catalog - тексты по языкам. Чтобы добавить язык, достаточно файла с картой ключ -> текст
+и строки здесь: недостающие ключи берутся из языка по умолчанию
+*/
+var catalog = map[string]map[string]string{
+	"ru": ru,
+	"en": en,
+}
+
+// Languages - поддерживаемые языки, по алфавиту
+func Languages() []string {
+	languages := make([]string, 0, len(catalog))
+	for language := range catalog {
+		languages = append(languages, language)
+	}
+	slices.Sort(languages)
+
+	return languages
+}
+
+func Supported(language string) bool {
+	_, ok := catalog[language]
+	return ok
+}
+
+// Match - поддерживаемый язык по коду из Telegram или заголовка (en-US -> en), иначе Default
+func Match(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if base, _, ok := strings.Cut(code, "-"); ok {
+		code = base
+	}
+
+	if Supported(code) {
+		return code
+	}
+
+	return Default
+}
+
+// T - текст по ключу на языке language, args подставляются как в fmt.Sprintf
+func T(language, key string, args ...any) string {
+	text, ok := catalog[language][key]
+	if !ok {
+		text, ok = catalog[Default][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+
+	return fmt.Sprintf(text, args...)
+}
+
+/*
+FromAcceptLanguage - самый предпочтительный поддерживаемый язык из заголовка Accept-Language
+(например "en-US,en;q=0.9,ru;q=0.8"). Пустая строка, если ни один не поддерживается
+*/
+func FromAcceptLanguage(header string) string {
+	best, bestQuality := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if Supported(base) && quality > bestQuality {
+			best, bestQuality = base, quality
+		}
+	}
+
+	return best
+}
+
+// errorKeys - ключи каталога для ошибок API, которые отдаются по-английски
+var errorKeys = map[string]string{
+	"entry not found":                        "error.not_found",
+	"not enough permissions":                 "error.not_enough_perms",
+	"muted in this playlist":                 "error.muted",
+	"invalid token":                          "error.invalid_token",
+	"invalid init data":                      "error.invalid_init_data",
+	"invalid login widget data":              "error.invalid_login",
+	"invalid input":                          "error.invalid_input",
+	"internal server error":                  "error.internal",
+	"unauthorized":                           "error.unauthorized",
+	"admin only":                             "error.admin_only",
+	"token is not allowed for this playlist": "error.token_playlist",
+	"validation failed":                      "error.validation",
+}
+
+// Error - перевести текст ошибки API. Для "invalid input: подробности" переводится только начало, неизвестные ошибки не меняются
+func Error(language, message string) string {
+	if key, ok := errorKeys[message]; ok {
+		return T(language, key)
+	}
+
+	if prefix, details, ok := strings.Cut(message, ": "); ok {
+		if key, ok := errorKeys[prefix]; ok {
+			return T(language, key) + ": " + details
+		}
+	}
+
+	return message
+}
@@ -0,0 +1,95 @@
+package i18n
+
+var ru = map[string]string{
+	"language.name": "Русский",
+
+	"start.text": "Привет, я Лотти! Бот для модерации плейлистов.\n\n" +
+		"Добавь меня в группу и я подгружу данные из неё, если хочешь приватный плейлист только для тебя - зайди в миниапп.\n\n" +
+		"Чтобы предложить трек прямо из группы, напиши @%s и название трека.\n\n" +
+		"Для управления плейлистами, к которым у тебя есть доступ - зайди в миниапп)",
+
+	"group.welcome": "Привет, я Лотти - бот для управления плейлистами!\n\n" +
+		"Можешь дать мне права администратора (чтобы я мог видеть лог AKA recent actions/недавние действия, а также всех участников и администраторов)",
+	"group.admin_thanks": "Респект тебе за админку, сейчас создам плейлист!",
+
+	"common.no_playlist":      "У этой группы нет плейлиста",
+	"common.not_enough_perms": "Недостаточно прав",
+	"common.failed":           "Что-то пошло не так",
+	"common.done":             "Готово",
+
+	"cmd.start":    "О боте",
+	"cmd.help":     "Список команд",
+	"cmd.playlist": "Плейлист группы",
+	"cmd.pending":  "Треки на модерации",
+	"cmd.top":      "Самые частые исполнители",
+	"cmd.mine":     "Мои предложенные треки",
+	"cmd.mute":     "Запретить предлагать треки (ответом на сообщение)",
+	"cmd.unmute":   "Снять запрет (ответом на сообщение)",
+	"cmd.sync":     "Сверить участников плейлиста с группой",
+	"cmd.lang":     "Язык бота в группе",
+
+	"cmd.newplaylist": "Отдельный плейлист для этой темы",
+
+	"playlist.info": "«%s»\n\nТреков: %d, из них одобрено: %d\nДлительность одобренных: %s",
+	"playlist.open": "Открыть плейлист",
+
+	"pending.empty": "Треков на модерации нет",
+	"pending.list":  "На модерации: %d\n\n%s",
+
+	"top.empty": "В плейлисте пока нет треков",
+	"top.list":  "Самые частые исполнители:\n\n%s",
+
+	"mine.empty":      "Ты пока не предлагал треков",
+	"mine.list":       "Твои последние треки:\n\n%s",
+	"status.pending":  "на модерации",
+	"status.approved": "одобрен",
+	"status.declined": "отклонён",
+
+	"help.text": "Команды в группе с плейлистом:\n\n%s\n\n" +
+		"Чтобы предложить трек, напиши @%s и название трека или открой миниапп",
+
+	"inline.submitted":  "Трек предложен в плейлист",
+	"inline.muted":      "Тебе запрещено предлагать треки в этот плейлист",
+	"inline.not_member": "Ты не участник плейлиста этой группы",
+
+	"mute.done":                   "Готово, больше не может предлагать треки",
+	"mute.done_for":               "Готово, больше не может предлагать треки (на %s)",
+	"unmute.done":                 "Готово, снова может предлагать треки",
+	"moderation.reply_needed":     "Ответь командой на сообщение участника",
+	"moderation.member_not_found": "Участник не найден в плейлисте",
+	"moderation.invalid":          "Так нельзя: %s",
+
+	"sync.failed":      "Не удалось получить участников группы",
+	"sync.admins_only": "Команда доступна только админам группы",
+	"sync.up_to_date":  "Участники уже совпадают с группой",
+	"sync.done":        "Готово: добавлено %d, изменены роли у %d, удалено %d",
+
+	"card.text":             "Новый трек в «%s»\n\n%s - %s\n%s\n\nПредложил: %s",
+	"card.approve":          "Одобрить",
+	"card.decline":          "Отклонить",
+	"card.approved":         "Одобрил",
+	"card.declined":         "Отклонил",
+	"card.outdated":         "Устаревшая карточка",
+	"card.already_reviewed": "Трек уже рассмотрен или у тебя нет доступа к плейлисту",
+
+	"lang.current": "Язык бота в этой группе: %s\n\nСменить: /lang <код>, доступны: %s",
+	"lang.changed": "Готово, теперь я говорю по-русски",
+	"lang.unknown": "Такого языка нет, доступны: %s",
+
+	"topic.outside": "Команда работает только внутри темы форума",
+	"topic.exists":  "У этой темы уже есть плейлист «%s»",
+	"topic.created": "Готово, у темы теперь свой плейлист «%s». Треки, предложенные здесь, попадут в него",
+
+	"error.not_found":         "запись не найдена",
+	"error.not_enough_perms":  "недостаточно прав",
+	"error.muted":             "вам запрещено предлагать треки в этот плейлист",
+	"error.invalid_token":     "недействительный токен",
+	"error.invalid_init_data": "недействительные данные Telegram",
+	"error.invalid_login":     "недействительные данные входа через Telegram",
+	"error.invalid_input":     "некорректные данные",
+	"error.internal":          "внутренняя ошибка сервера",
+	"error.unauthorized":      "требуется авторизация",
+	"error.admin_only":        "только для администраторов",
+	"error.token_playlist":    "токену недоступен этот плейлист",
+	"error.validation":        "ошибка валидации",
+}
@@ -0,0 +1,161 @@
+package images
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// keyPattern - допустимые ключи кеша, они же имена файлов
+var keyPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+type cacheEntry struct {
+	size int64
+	used time.Time
+}
+
+/*
+Cache - кеш картинок на диске с ограничением общего размера. Когда размер превышен,
+удаляются файлы, которые дольше всех не читали. Время чтения хранится в памяти и в mtime файла,
+так что после перезапуска порядок вытеснения сохраняется
+*/
+type Cache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	total   int64
+}
+
+// NewCache - открыть кеш в папке dir, уже лежащие там файлы учитываются в размере
+func NewCache(dir string, maxBytes int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &Cache{dir: dir, maxBytes: maxBytes, entries: make(map[string]*cacheEntry)}
+	for _, file := range files {
+		if !file.Type().IsRegular() || !keyPattern.MatchString(file.Name()) {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		cache.entries[file.Name()] = &cacheEntry{size: info.Size(), used: info.ModTime()}
+		cache.total += info.Size()
+	}
+
+	cache.mu.Lock()
+	cache.evict()
+	cache.mu.Unlock()
+
+	return cache, nil
+}
+
+func (c *Cache) Get(key string) ([]byte, bool) {
+	if !keyPattern.MatchString(key) {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok {
+		entry.used = time.Now()
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		return nil, false
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(c.dir, key), now, now)
+
+	return data, true
+}
+
+// Put - сохранить файл и вытеснить старые, если кеш переполнен. Файлы больше всего кеша не сохраняются
+func (c *Cache) Put(key string, data []byte) error {
+	if !keyPattern.MatchString(key) {
+		return errors.New("invalid cache key: " + key)
+	}
+	if int64(len(data)) > c.maxBytes {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, key)); err != nil {
+		return err
+	}
+
+	if entry, ok := c.entries[key]; ok {
+		c.total -= entry.size
+	}
+	c.entries[key] = &cacheEntry{size: int64(len(data)), used: time.Now()}
+	c.total += int64(len(data))
+
+	c.evict()
+
+	return nil
+}
+
+// evict - удалять самые давно прочитанные файлы, пока кеш не влезет в maxBytes. Вызывается под mu
+func (c *Cache) evict() {
+	if c.total <= c.maxBytes {
+		return
+	}
+
+	keys := make([]string, 0, len(c.entries))
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return c.entries[a].used.Compare(c.entries[b].used)
+	})
+
+	for _, key := range keys {
+		if c.total <= c.maxBytes {
+			break
+		}
+
+		if err := os.Remove(filepath.Join(c.dir, key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		c.total -= c.entries[key].size
+		delete(c.entries, key)
+	}
+}
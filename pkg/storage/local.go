@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheControl - заголовок для отдачи файлов: ключи не перезаписываются, так что кешировать можно навсегда
+const CacheControl = "public, max-age=31536000, immutable"
+
+// Local - файлы в папке на диске, раздаются самим бэкендом по publicUrl
+type Local struct {
+	dir       string
+	publicUrl string
+}
+
+func NewLocal(dir, publicUrl string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Local{dir: dir, publicUrl: strings.TrimSuffix(publicUrl, "/")}, nil
+}
+
+// Put - записать файл атомарно: сначала во временный файл, потом переименовать
+func (s *Local) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *Local) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Local) URL(key string) string {
+	return s.publicUrl + "/" + key
+}
+
+// path - путь к файлу на диске. Ключи с .. отклоняются, чтобы не выйти за пределы папки
+func (s *Local) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(key) {
+		return "", errors.New("invalid storage key: " + key)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
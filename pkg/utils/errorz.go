@@ -11,6 +11,10 @@ var (
 	ErrNotEnoughPerms  = errors.New("not enough permissions")
 	ErrInvalidToken    = errors.New("invalid token")
 	ErrInvalidInitData = errors.New("invalid init data")
+	ErrInvalidLogin    = errors.New("invalid login widget data")
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrMuted           = errors.New("muted in this playlist")
+	ErrOwnerKept       = errors.New("playlist owner keeps the role")
 )
 
 func Convert(functionError error) error {
@@ -22,6 +26,10 @@ func Convert(functionError error) error {
 		return huma.Error403Forbidden("not enough permissions")
 	}
 
+	if errors.Is(functionError, ErrMuted) {
+		return huma.Error403Forbidden("muted in this playlist")
+	}
+
 	if errors.Is(functionError, ErrInvalidToken) {
 		return huma.Error401Unauthorized("invalid token")
 	}
@@ -30,5 +38,13 @@ func Convert(functionError error) error {
 		return huma.Error401Unauthorized("invalid init data")
 	}
 
+	if errors.Is(functionError, ErrInvalidLogin) {
+		return huma.Error401Unauthorized("invalid login widget data")
+	}
+
+	if errors.Is(functionError, ErrInvalidInput) {
+		return huma.Error422UnprocessableEntity(functionError.Error())
+	}
+
 	return huma.Error500InternalServerError("internal server error")
 }
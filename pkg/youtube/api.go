@@ -16,6 +16,8 @@ import (
 	"github.com/klauspost/compress/zstd"
 )
 
+const clientVersion = "1.20251110.03.00"
+
 type API struct {
 	client *http.Client
 }
@@ -24,31 +26,182 @@ func New() *API {
 	return &API{client: &http.Client{}}
 }
 
+func requestContext() SearchRequestContext {
+	return SearchRequestContext{
+		Client: SearchRequestClient{
+			Hl:            "en",
+			Gl:            "US",
+			ClientName:    "WEB_REMIX",
+			ClientVersion: clientVersion,
+			OriginalUrl:   "https://music.youtube.com/",
+		},
+		User:    SearchRequestUser{LockedSafetyMode: false},
+		Request: SearchRequestOptions{UseSsl: true},
+	}
+}
+
 func (s *API) Search(ctx context.Context, query string) ([]dto.Track, error) {
 	body := &SearchRequest{
-		Query:  query,
-		Params: FILTER_SONGS,
-		Context: SearchRequestContext{
-			Client: SearchRequestClient{
-				Hl:            "en",
-				Gl:            "US",
-				ClientName:    "WEB_REMIX",
-				ClientVersion: "1.20251110.03.00",
-				OriginalUrl:   "https://music.youtube.com/",
-			},
-			User:    SearchRequestUser{LockedSafetyMode: false},
-			Request: SearchRequestOptions{UseSsl: true},
-		},
+		Query:   query,
+		Params:  FILTER_SONGS,
+		Context: requestContext(),
+	}
+
+	var result SearchResponse
+	if err := s.call(ctx, "search", body, &result); err != nil {
+		return nil, err
+	}
+
+	var data []struct {
+		Data RawYtMusicSong `json:"musicResponsiveListItemRenderer"`
+	}
+
+	for _, tab := range result.Contents.TabbedSearchResultsRenderer.Tabs {
+		for _, content := range tab.TabRenderer.Content.SectionListRenderer.Contents {
+			if content.MusicShelfRenderer.Contents == nil {
+				continue
+			}
+
+			data = *content.MusicShelfRenderer.Contents
+			break
+		}
 	}
 
+	if data == nil {
+		return nil, errors.New("cannot find music shelf")
+	}
+
+	tracks := make([]dto.Track, len(data))
+	for i, result := range data {
+		track, err := parseRaw(&result.Data)
+		if err != nil {
+			return nil, errors.New("cannot parse music track")
+		}
+
+		tracks[i] = track
+	}
+
+	return tracks, nil
+}
+
+// GetTrack - получить трек по ID видео
+func (s *API) GetTrack(ctx context.Context, id string) (dto.Track, error) {
+	body := &PlayerRequest{
+		VideoId: id,
+		Context: requestContext(),
+	}
+
+	var result PlayerResponse
+	if err := s.call(ctx, "player", body, &result); err != nil {
+		return dto.Track{}, err
+	}
+
+	if result.VideoDetails.VideoId == "" {
+		return dto.Track{}, fmt.Errorf("track %s not found: %s", id, result.PlayabilityStatus.Status)
+	}
+
+	return parseDetails(&result.VideoDetails)
+}
+
+// maxPlaylistPages - ограничение на число страниц плейлиста, страница - около 100 треков
+const maxPlaylistPages = 50
+
+/*
+GetPlaylist - получить название и треки плейлиста, проходя по всем страницам browse.
+truncated - плейлист длиннее maxPlaylistPages страниц и вернулись не все треки
+*/
+func (s *API) GetPlaylist(ctx context.Context, id string) (title string, tracks []dto.Track, truncated bool, err error) {
+	var result BrowseResponse
+	if err := s.call(ctx, "browse", &BrowseRequest{Context: requestContext(), BrowseId: "VL" + id}, &result); err != nil {
+		return "", nil, false, err
+	}
+
+	if runs := result.Header.Detail.Title.Runs; len(runs) > 0 {
+		title = runs[0].Text
+	}
+	for _, tab := range result.Contents.TwoColumnBrowseResultsRenderer.Tabs {
+		for _, content := range tab.TabRenderer.Content.SectionListRenderer.Contents {
+			if runs := content.Header.Title.Runs; len(runs) > 0 {
+				title = runs[0].Text
+			}
+		}
+	}
+
+	var shelf *PlaylistShelf
+	for _, content := range result.Contents.TwoColumnBrowseResultsRenderer.SecondaryContents.SectionListRenderer.Contents {
+		if content.Shelf != nil {
+			shelf = content.Shelf
+			break
+		}
+	}
+
+	if shelf == nil {
+		return "", nil, false, errors.New("cannot find playlist shelf")
+	}
+
+	items, token := shelf.Contents, shelfContinuation(shelf)
+	for page := 0; ; page++ {
+		for _, item := range items {
+			if item.Continuation != nil {
+				token = item.Continuation.Endpoint.Command.Token
+				continue
+			}
+
+			if item.Data == nil {
+				continue
+			}
+
+			if track, ok := parsePlaylistItem(item.Data); ok {
+				tracks = append(tracks, track)
+			}
+		}
+
+		if token == "" {
+			break
+		}
+		if page == maxPlaylistPages {
+			truncated = true
+			break
+		}
+
+		var next BrowseResponse
+		if err := s.call(ctx, "browse", &BrowseRequest{Context: requestContext(), Continuation: token}, &next); err != nil {
+			return "", nil, false, err
+		}
+
+		items, token = nil, ""
+		for _, action := range next.OnResponseReceivedActions {
+			items = append(items, action.Append.Items...)
+		}
+		if next.ContinuationContents.Shelf != nil {
+			items = append(items, next.ContinuationContents.Shelf.Contents...)
+			token = shelfContinuation(next.ContinuationContents.Shelf)
+		}
+	}
+
+	return title, tracks, truncated, nil
+}
+
+func shelfContinuation(shelf *PlaylistShelf) string {
+	for _, continuation := range shelf.Continuations {
+		if continuation.Next.Continuation != "" {
+			return continuation.Next.Continuation
+		}
+	}
+
+	return ""
+}
+
+// call - выполнить запрос к InnerTube API
+func (s *API) call(ctx context.Context, endpoint string, body, result any) error {
 	bodyBytes, err := sonic.Marshal(body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://music.youtube.com/youtubei/v1/search", bytes.NewReader(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://music.youtube.com/youtubei/v1/"+endpoint, bytes.NewReader(bodyBytes))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	req.Header.Set("Accept", "*/*")
@@ -75,13 +228,13 @@ func (s *API) Search(ctx context.Context, query string) ([]dto.Track, error) {
 	req.Header.Set("Sec-Fetch-Site", "same-origin")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
 	req.Header.Set("X-Youtube-Client-Name", "67")
-	req.Header.Set("X-Youtube-Client-Version", "1.20251110.03.00")
+	req.Header.Set("X-Youtube-Client-Version", clientVersion)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Youtube-Bootstrap-Logged-In", "false")
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -90,6 +243,10 @@ func (s *API) Search(ctx context.Context, query string) ([]dto.Track, error) {
 		}
 	}(resp.Body)
 
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("youtube %s: unexpected status %d", endpoint, resp.StatusCode)
+	}
+
 	encoding := resp.Header.Get("Content-Encoding")
 	var reader io.Reader
 
@@ -97,7 +254,7 @@ func (s *API) Search(ctx context.Context, query string) ([]dto.Track, error) {
 	case "gzip":
 		gzReader, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+			return fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		defer func(gzReader *gzip.Reader) {
 			err := gzReader.Close()
@@ -114,7 +271,7 @@ func (s *API) Search(ctx context.Context, query string) ([]dto.Track, error) {
 		// Handle zstd compression (you'll need a zstd decoder package)
 		zstdReader, err := zstd.NewReader(resp.Body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+			return fmt.Errorf("failed to create zstd reader: %w", err)
 		}
 		defer zstdReader.Close()
 		reader = zstdReader
@@ -126,44 +283,8 @@ func (s *API) Search(ctx context.Context, query string) ([]dto.Track, error) {
 
 	respBytes, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, err
-	}
-
-	println(string(respBytes))
-
-	var result SearchResponse
-	if err := sonic.Unmarshal(respBytes, &result); err != nil {
-		return nil, err
+		return err
 	}
 
-	var data []struct {
-		Data RawYtMusicSong `json:"musicResponsiveListItemRenderer"`
-	}
-
-	for _, tab := range result.Contents.TabbedSearchResultsRenderer.Tabs {
-		for _, content := range tab.TabRenderer.Content.SectionListRenderer.Contents {
-			if content.MusicShelfRenderer.Contents == nil {
-				continue
-			}
-
-			data = *content.MusicShelfRenderer.Contents
-			break
-		}
-	}
-
-	if data == nil {
-		return nil, errors.New("cannot find music shelf")
-	}
-
-	tracks := make([]dto.Track, len(data))
-	for i, result := range data {
-		track, err := parseRaw(&result.Data)
-		if err != nil {
-			return nil, errors.New("cannot parse music track")
-		}
-
-		tracks[i] = track
-	}
-
-	return tracks, nil
+	return sonic.Unmarshal(respBytes, result)
 }
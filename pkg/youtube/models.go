@@ -65,9 +65,13 @@ type Track struct {
 }
 
 type RawYtMusicSong struct {
-	Thumbnail   Thumbnail    `json:"thumbnail"`
-	FlexColumns []FlexColumn `json:"flexColumns"`
-	Badges      []Badge      `json:"badges,omitempty"`
+	Thumbnail        Thumbnail     `json:"thumbnail"`
+	FlexColumns      []FlexColumn  `json:"flexColumns"`
+	FixedColumns     []FixedColumn `json:"fixedColumns,omitempty"`
+	Badges           []Badge       `json:"badges,omitempty"`
+	PlaylistItemData struct {
+		VideoId string `json:"videoId"`
+	} `json:"playlistItemData,omitempty"`
 }
 
 type Thumbnail struct {
@@ -102,3 +106,107 @@ type Badge struct {
 		} `json:"icon"`
 	} `json:"musicInlineBadgeRenderer"`
 }
+
+type PlayerRequest struct {
+	Context SearchRequestContext `json:"context"`
+	VideoId string               `json:"videoId"`
+}
+
+type PlayerResponse struct {
+	PlayabilityStatus struct {
+		Status string `json:"status"`
+	} `json:"playabilityStatus"`
+	VideoDetails VideoDetails `json:"videoDetails"`
+}
+
+type VideoDetails struct {
+	VideoId       string `json:"videoId"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	LengthSeconds string `json:"lengthSeconds"`
+	Thumbnail     struct {
+		Thumbnails []struct {
+			Url    string `json:"url"`
+			Width  int    `json:"width"`
+			Height int    `json:"height"`
+		} `json:"thumbnails"`
+	} `json:"thumbnail"`
+}
+
+type FixedColumn struct {
+	Renderer struct {
+		Data Runs `json:"text"`
+	} `json:"musicResponsiveListItemFixedColumnRenderer"`
+}
+
+type Runs struct {
+	Runs []struct {
+		Text string `json:"text"`
+	} `json:"runs"`
+}
+
+type BrowseRequest struct {
+	Context      SearchRequestContext `json:"context"`
+	BrowseId     string               `json:"browseId,omitempty"`
+	Continuation string               `json:"continuation,omitempty"`
+}
+
+type PlaylistItem struct {
+	Data         *RawYtMusicSong `json:"musicResponsiveListItemRenderer,omitempty"`
+	Continuation *struct {
+		Endpoint struct {
+			Command struct {
+				Token string `json:"token"`
+			} `json:"continuationCommand"`
+		} `json:"continuationEndpoint"`
+	} `json:"continuationItemRenderer,omitempty"`
+}
+
+type PlaylistShelf struct {
+	Contents      []PlaylistItem `json:"contents"`
+	Continuations []struct {
+		Next struct {
+			Continuation string `json:"continuation"`
+		} `json:"nextContinuationData"`
+	} `json:"continuations,omitempty"`
+}
+
+type BrowseResponse struct {
+	Header struct {
+		Detail struct {
+			Title Runs `json:"title"`
+		} `json:"musicDetailHeaderRenderer"`
+	} `json:"header"`
+	Contents struct {
+		TwoColumnBrowseResultsRenderer struct {
+			Tabs []struct {
+				TabRenderer struct {
+					Content struct {
+						SectionListRenderer struct {
+							Contents []struct {
+								Header struct {
+									Title Runs `json:"title"`
+								} `json:"musicResponsiveHeaderRenderer"`
+							} `json:"contents"`
+						} `json:"sectionListRenderer"`
+					} `json:"content"`
+				} `json:"tabRenderer"`
+			} `json:"tabs"`
+			SecondaryContents struct {
+				SectionListRenderer struct {
+					Contents []struct {
+						Shelf *PlaylistShelf `json:"musicPlaylistShelfRenderer,omitempty"`
+					} `json:"contents"`
+				} `json:"sectionListRenderer"`
+			} `json:"secondaryContents"`
+		} `json:"twoColumnBrowseResultsRenderer"`
+	} `json:"contents"`
+	OnResponseReceivedActions []struct {
+		Append struct {
+			Items []PlaylistItem `json:"continuationItems"`
+		} `json:"appendContinuationItemsAction"`
+	} `json:"onResponseReceivedActions,omitempty"`
+	ContinuationContents struct {
+		Shelf *PlaylistShelf `json:"musicPlaylistShelfContinuation,omitempty"`
+	} `json:"continuationContents"`
+}
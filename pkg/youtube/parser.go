@@ -3,6 +3,7 @@ package youtube
 import (
 	"backend/internal/transport/api/dto"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -107,3 +108,128 @@ func parseRaw(song *RawYtMusicSong) (dto.Track, error) {
 		Explicit:  checkExplicit(song.Badges),
 	}, nil
 }
+
+// TrackURL - ссылка на трек в Youtube Music
+func TrackURL(id string) string {
+	return "https://music.youtube.com/watch?v=" + id
+}
+
+func parseDetails(details *VideoDetails) (dto.Track, error) {
+	length, err := strconv.Atoi(details.LengthSeconds)
+	if err != nil {
+		return dto.Track{}, fmt.Errorf("invalid length: %w", err)
+	}
+
+	thumbnail := ""
+	if items := details.Thumbnail.Thumbnails; len(items) > 0 {
+		thumbnail = items[len(items)-1].Url
+	}
+
+	return dto.Track{
+		Id:        details.VideoId,
+		Title:     details.Title,
+		Authors:   strings.TrimSuffix(details.Author, " - Topic"),
+		Length:    int32(length),
+		Thumbnail: thumbnail,
+	}, nil
+}
+
+var videoIdRe = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// ParseTrackID - достать ID видео из ссылки на Youtube / Youtube Music
+func ParseTrackID(link string) (string, bool) {
+	link = strings.TrimSpace(link)
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", false
+	}
+
+	var id string
+	switch strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") {
+	case "youtu.be":
+		id = strings.Trim(u.Path, "/")
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+		if v := u.Query().Get("v"); v != "" {
+			id = v
+		} else if rest, ok := strings.CutPrefix(u.Path, "/shorts/"); ok {
+			id = strings.Trim(rest, "/")
+		}
+	}
+
+	if !videoIdRe.MatchString(id) {
+		return "", false
+	}
+
+	return id, true
+}
+
+var playlistIdRe = regexp.MustCompile(`^[A-Za-z0-9_-]{10,}$`)
+
+// ParsePlaylistID - достать ID плейлиста из ссылки на Youtube / Youtube Music или из самого ID
+func ParsePlaylistID(link string) (string, bool) {
+	link = strings.TrimSpace(link)
+
+	id := link
+	if strings.Contains(link, "/") {
+		if !strings.Contains(link, "://") {
+			link = "https://" + link
+		}
+
+		u, err := url.Parse(link)
+		if err != nil {
+			return "", false
+		}
+
+		id = u.Query().Get("list")
+	}
+
+	id = strings.TrimPrefix(id, "VL")
+	if !playlistIdRe.MatchString(id) {
+		return "", false
+	}
+
+	return id, true
+}
+
+// parsePlaylistItem - трек из плейлиста. У недоступных треков нет ID, для них ok = false
+func parsePlaylistItem(song *RawYtMusicSong) (dto.Track, bool) {
+	if len(song.FlexColumns) < 2 || len(song.FlexColumns[0].Renderer.Data.Runs) == 0 {
+		return dto.Track{}, false
+	}
+
+	title, id := getTitleAndID(song.FlexColumns[0])
+	if song.PlaylistItemData.VideoId != "" {
+		id = song.PlaylistItemData.VideoId
+	}
+	if id == "" {
+		return dto.Track{}, false
+	}
+
+	artists := ""
+	for _, run := range song.FlexColumns[1].Renderer.Data.Runs {
+		artists += run.Text
+	}
+
+	duration := 0
+	if len(song.FixedColumns) > 0 && len(song.FixedColumns[0].Renderer.Data.Runs) > 0 {
+		duration, _ = parseTime(song.FixedColumns[0].Renderer.Data.Runs[0].Text)
+	}
+
+	thumbnail := ""
+	if len(song.Thumbnail.Renderer.Data.Items) > 0 {
+		thumbnail = getBestThumbnail(song.Thumbnail)
+	}
+
+	return dto.Track{
+		Id:        id,
+		Title:     title,
+		Authors:   artists,
+		Length:    int32(duration),
+		Thumbnail: thumbnail,
+		Explicit:  checkExplicit(song.Badges),
+	}, true
+}
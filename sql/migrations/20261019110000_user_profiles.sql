@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS first_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS username TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS language_code TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS photo_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS photo_url,
+    DROP COLUMN IF EXISTS language_code,
+    DROP COLUMN IF EXISTS username,
+    DROP COLUMN IF EXISTS last_name,
+    DROP COLUMN IF EXISTS first_name;
+-- +goose StatementEnd
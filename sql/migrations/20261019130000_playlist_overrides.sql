@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS playlist_overrides (
+    playlist_id TEXT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    role playlist_role NOT NULL,
+    capability TEXT NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    PRIMARY KEY (playlist_id, role, capability)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS playlist_overrides;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- если владельцев несколько, остаётся один, остальные становятся модераторами
+UPDATE playlist_permissions p
+SET role = 'moderator'
+WHERE role = 'owner' AND user_id <> (
+    SELECT min(o.user_id) FROM playlist_permissions o
+    WHERE o.playlist_id = p.playlist_id AND o.role = 'owner'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_single_owner ON playlist_permissions (playlist_id) WHERE role = 'owner';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP INDEX IF EXISTS idx_permissions_single_owner;
+-- +goose StatementEnd
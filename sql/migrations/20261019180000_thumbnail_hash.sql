@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- хеш как sha256.Sum256 в Go: байты ссылки в UTF-8. convert_to только STABLE, а для индекса нужна IMMUTABLE функция,
+-- кодировка базы не меняется, поэтому обёртка помечена IMMUTABLE
+CREATE OR REPLACE FUNCTION thumbnail_hash(url TEXT)
+    RETURNS TEXT AS $$
+    SELECT encode(sha256(convert_to(url, 'UTF8')), 'hex');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX IF NOT EXISTS idx_tracks_thumbnail_hash ON tracks (thumbnail_hash(thumbnail));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP INDEX IF EXISTS idx_tracks_thumbnail_hash;
+
+DROP FUNCTION IF EXISTS thumbnail_hash(TEXT);
+-- +goose StatementEnd
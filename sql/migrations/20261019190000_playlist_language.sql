@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE playlists ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT 'ru';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE playlists DROP COLUMN IF EXISTS language;
+-- +goose StatementEnd
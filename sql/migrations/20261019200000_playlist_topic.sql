@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE playlists ADD COLUMN IF NOT EXISTS topic_id INTEGER NOT NULL DEFAULT 0;
+
+-- если у группы несколько живых плейлистов, остаётся самый новый (ID - ULID), остальные удаляются мягко
+UPDATE playlists p
+SET deleted_at = now()
+WHERE p.telegram_id <> 0 AND p.deleted_at IS NULL AND p.id <> (
+    SELECT max(n.id) FROM playlists n
+    WHERE n.telegram_id = p.telegram_id AND n.topic_id = p.topic_id AND n.deleted_at IS NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_playlists_group_topic ON playlists (telegram_id, topic_id) WHERE telegram_id <> 0 AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP INDEX IF EXISTS idx_playlists_group_topic;
+
+ALTER TABLE playlists DROP COLUMN IF EXISTS topic_id;
+-- +goose StatementEnd
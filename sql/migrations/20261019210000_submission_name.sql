@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE track_submissions ADD COLUMN IF NOT EXISTS submitter_name TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE track_submissions DROP COLUMN IF EXISTS submitter_name;
+-- +goose StatementEnd
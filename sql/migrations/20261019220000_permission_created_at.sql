@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- у существующих участников время одинаковое, порядок между ними - по ID
+ALTER TABLE playlist_permissions ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE playlist_permissions DROP COLUMN IF EXISTS created_at;
+-- +goose StatementEnd
@@ -1,18 +1,18 @@
 -- name: CreatePlaylist :exec
-INSERT INTO playlists (id, title, thumbnail, tracks, allowed_tracks, type, external_id, telegram_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+INSERT INTO playlists (id, title, thumbnail, tracks, allowed_tracks, type, external_id, telegram_id, topic_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
 
 -- name: EditPlaylist :exec
 UPDATE playlists
 SET
-    title = COALESCE($2, title),
-    thumbnail = COALESCE($3, thumbnail),
-    tracks = COALESCE($4, tracks),
-    allowed_tracks = COALESCE($5, allowed_tracks),
-    type = COALESCE($6, type),
-    external_id = COALESCE($7, external_id),
-    telegram_id = COALESCE($8, external_id)
-WHERE id = $1;
+    title = COALESCE(sqlc.narg(title), title),
+    thumbnail = COALESCE(sqlc.narg(thumbnail), thumbnail),
+    tracks = COALESCE(sqlc.narg(tracks), tracks),
+    allowed_tracks = COALESCE(sqlc.narg(allowed_tracks), allowed_tracks),
+    type = COALESCE(sqlc.narg(type), type),
+    external_id = COALESCE(sqlc.narg(external_id), external_id),
+    telegram_id = COALESCE(sqlc.narg(telegram_id), telegram_id)
+WHERE id = sqlc.arg(id);
 
 -- name: DeletePlaylist :exec
 DELETE FROM playlists WHERE id = $1;
@@ -24,7 +24,7 @@ SELECT
 FROM playlists pl
          JOIN playlist_permissions p ON pl.id = p.playlist_id
          JOIN users u ON p.user_id = u.id  -- Join users table
-WHERE p.user_id = $1;
+WHERE p.user_id = $1 AND pl.deleted_at IS NULL;
 
 -- name: GetUserPlaylistById :one
 SELECT
@@ -33,13 +33,15 @@ SELECT
 FROM playlist_permissions p
          JOIN playlists pl ON p.playlist_id = pl.id
          JOIN users u ON p.user_id = u.id  -- Join users table
-WHERE p.playlist_id = $1 AND  p.user_id = $2;
+WHERE p.playlist_id = $1 AND  p.user_id = $2 AND pl.deleted_at IS NULL;
 
 -- name: GetGroupPlaylist :one
 SELECT
     *
 FROM playlists
-WHERE telegram_id = $1;
+WHERE telegram_id = sqlc.arg(telegram_id) AND topic_id IN (0, sqlc.arg(topic_id)::int) AND deleted_at IS NULL
+ORDER BY topic_id DESC
+LIMIT 1;
 
 -- name: GetTrackPlaylists :many
 -- param: TrackId text
@@ -49,7 +51,8 @@ FROM playlists pl
          JOIN playlist_permissions pp ON pl.id = pp.playlist_id
 WHERE
     pp.user_id = sqlc.arg(user_id)
-  AND sqlc.arg(track_id)::text = ANY(pl.tracks);
+  AND sqlc.arg(track_id)::text = ANY(pl.tracks)
+  AND pl.deleted_at IS NULL;
 
 -- name: CreateUser :exec
 INSERT INTO users (id) VALUES ($1);
@@ -57,6 +60,26 @@ INSERT INTO users (id) VALUES ($1);
 -- name: GetUserById :one
 SELECT * FROM users WHERE id = $1;
 
+-- name: UpsertUser :exec
+INSERT INTO users (id, first_name, last_name, username, language_code, photo_url, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, now())
+ON CONFLICT (id) DO UPDATE SET
+    first_name = EXCLUDED.first_name,
+    last_name = EXCLUDED.last_name,
+    username = EXCLUDED.username,
+    language_code = COALESCE(NULLIF(EXCLUDED.language_code, ''), users.language_code),
+    photo_url = COALESCE(NULLIF(EXCLUDED.photo_url, ''), users.photo_url),
+    updated_at = now();
+
+-- name: GetPlaylistMembers :many
+SELECT
+    u.*,
+    p.role
+FROM playlist_permissions p
+         JOIN users u ON p.user_id = u.id
+WHERE p.playlist_id = $1
+ORDER BY p.role DESC, u.id;
+
 -- name: CreateTrack :exec
 INSERT INTO tracks (id, title, authors, thumbnail, length, explicit)
 VALUES ($1, $2, $3, $4, $5, $6);
@@ -77,6 +100,229 @@ WHERE playlist_id = $1 AND user_id = $2;
 DELETE FROM playlist_permissions
 WHERE playlist_id = $1 AND user_id = $2;
 
+-- name: DemoteOwner :exec
+UPDATE playlist_permissions
+SET role = 'moderator'
+WHERE playlist_id = $1 AND role = 'owner' AND user_id <> $2;
+
 -- name: GetRole :one
 SELECT playlist_id FROM playlist_permissions
 WHERE user_id = $1 AND role = $2;
+
+-- name: GetTracksByIds :many
+SELECT * FROM tracks WHERE id = ANY(sqlc.arg(ids)::text[]);
+
+-- name: UpsertTrack :exec
+INSERT INTO tracks (id, title, authors, thumbnail, length, explicit)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO UPDATE SET
+    title = EXCLUDED.title,
+    authors = EXCLUDED.authors,
+    thumbnail = COALESCE(NULLIF(EXCLUDED.thumbnail, ''), tracks.thumbnail),
+    length = COALESCE(NULLIF(EXCLUDED.length, 0), tracks.length),
+    explicit = tracks.explicit OR EXCLUDED.explicit;
+
+-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, refresh_hash, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetSession :one
+SELECT * FROM sessions WHERE id = $1;
+
+-- name: GetSessionByRefresh :one
+SELECT * FROM sessions
+WHERE refresh_hash = sqlc.arg(hash) OR previous_hash = sqlc.arg(hash);
+
+-- name: RotateSession :execrows
+UPDATE sessions
+SET
+    previous_hash = refresh_hash,
+    refresh_hash = sqlc.arg(new_hash),
+    expires_at = sqlc.arg(expires_at)
+WHERE id = sqlc.arg(id) AND refresh_hash = sqlc.arg(old_hash) AND revoked_at IS NULL;
+
+-- name: RevokeSession :exec
+UPDATE sessions SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :exec
+UPDATE sessions SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: DeleteExpiredSessions :exec
+DELETE FROM sessions WHERE user_id = $1 AND expires_at < now();
+
+-- name: CreateApiToken :exec
+INSERT INTO api_tokens (id, user_id, name, token_hash, scopes, playlists, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetApiTokenByHash :one
+SELECT * FROM api_tokens WHERE token_hash = $1;
+
+-- name: GetUserApiTokens :many
+SELECT * FROM api_tokens
+WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+ORDER BY created_at;
+
+-- name: RevokeApiToken :execrows
+UPDATE api_tokens SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: TouchApiToken :exec
+UPDATE api_tokens SET last_used_at = now() WHERE id = $1;
+
+-- name: GetPlaylistOverrides :many
+SELECT * FROM playlist_overrides WHERE playlist_id = $1 ORDER BY role, capability;
+
+-- name: SetPlaylistOverride :exec
+INSERT INTO playlist_overrides (playlist_id, role, capability, allowed)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (playlist_id, role, capability) DO UPDATE SET allowed = EXCLUDED.allowed;
+
+-- name: DeletePlaylistOverride :exec
+DELETE FROM playlist_overrides WHERE playlist_id = $1 AND role = $2 AND capability = $3;
+
+-- name: MuteUser :exec
+INSERT INTO playlist_mutes (playlist_id, user_id, muted_by, reason, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (playlist_id, user_id) DO UPDATE SET
+    muted_by = EXCLUDED.muted_by,
+    reason = EXCLUDED.reason,
+    expires_at = EXCLUDED.expires_at,
+    created_at = now();
+
+-- name: UnmuteUser :execrows
+DELETE FROM playlist_mutes WHERE playlist_id = $1 AND user_id = $2;
+
+-- name: GetActiveMute :one
+SELECT * FROM playlist_mutes
+WHERE playlist_id = $1 AND user_id = $2 AND (expires_at IS NULL OR expires_at > now());
+
+-- name: GetPlaylistMutes :many
+SELECT
+    m.*,
+    u.first_name,
+    u.last_name,
+    u.username
+FROM playlist_mutes m
+         JOIN users u ON m.user_id = u.id
+WHERE m.playlist_id = $1 AND (m.expires_at IS NULL OR m.expires_at > now())
+ORDER BY m.created_at;
+
+-- name: UpsertRole :exec
+INSERT INTO playlist_permissions (playlist_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (playlist_id, user_id) DO UPDATE SET role = EXCLUDED.role;
+
+-- name: GetPlaylistById :one
+SELECT * FROM playlists WHERE id = $1;
+
+-- name: SetPlaylistDeleted :execrows
+UPDATE playlists
+SET deleted_at = CASE WHEN sqlc.arg(deleted)::bool THEN now() END
+WHERE id = sqlc.arg(id) AND (deleted_at IS NOT NULL) <> sqlc.arg(deleted)::bool;
+
+-- name: AdminSearchPlaylists :many
+SELECT
+    pl.*,
+    COALESCE((SELECT p.user_id FROM playlist_permissions p WHERE p.playlist_id = pl.id AND p.role = 'owner'), 0)::bigint AS owner_id
+FROM playlists pl
+WHERE (sqlc.arg(query)::text = ''
+    OR pl.id = sqlc.arg(query)::text
+    OR pl.telegram_id::text = sqlc.arg(query)::text
+    OR pl.title ILIKE '%' || sqlc.arg(query)::text || '%')
+  AND (pl.deleted_at IS NOT NULL) = sqlc.arg(deleted)::bool
+ORDER BY pl.id DESC
+LIMIT sqlc.arg(lim) OFFSET sqlc.arg(off);
+
+-- name: AdminSearchUsers :many
+SELECT * FROM users
+WHERE sqlc.arg(query)::text = ''
+   OR id::text = sqlc.arg(query)::text
+   OR username ILIKE '%' || sqlc.arg(query)::text || '%'
+   OR (first_name || ' ' || last_name) ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY id
+LIMIT sqlc.arg(lim) OFFSET sqlc.arg(off);
+
+-- name: LogAction :exec
+INSERT INTO moderation_log (playlist_id, actor_id, action, target_user_id, track_id, details)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetPlaylistHistory :many
+SELECT * FROM moderation_log
+WHERE playlist_id = $1
+ORDER BY id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: AddSubmission :exec
+INSERT INTO track_submissions (playlist_id, track_id, user_id, submitter_name)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (playlist_id, track_id) DO UPDATE SET user_id = EXCLUDED.user_id, submitter_name = EXCLUDED.submitter_name, created_at = now();
+
+-- name: GetUserSubmissions :many
+SELECT
+    s.track_id, s.created_at,
+    t.title, t.authors, t.thumbnail, t.length, t.explicit
+FROM track_submissions s
+         JOIN tracks t ON s.track_id = t.id
+WHERE s.playlist_id = $1 AND s.user_id = $2
+ORDER BY s.created_at DESC
+LIMIT $3;
+
+-- name: GetGroupPlaylists :many
+SELECT id, telegram_id FROM playlists
+WHERE telegram_id <> 0 AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: MigratePlaylist :one
+UPDATE playlists SET telegram_id = sqlc.arg(new_id)
+WHERE telegram_id = sqlc.arg(old_id) AND deleted_at IS NULL
+RETURNING id;
+
+-- name: GetThumbnailByHash :one
+SELECT thumbnail FROM tracks
+WHERE thumbnail_hash(thumbnail) = sqlc.arg(hash)::text
+LIMIT 1;
+
+-- name: GetGroupLanguage :one
+SELECT language FROM playlists
+WHERE telegram_id = sqlc.arg(telegram_id) AND topic_id IN (0, sqlc.arg(topic_id)::int) AND deleted_at IS NULL
+ORDER BY topic_id DESC
+LIMIT 1;
+
+-- name: SetPlaylistLanguage :exec
+UPDATE playlists SET language = $2
+WHERE id = $1;
+
+-- name: GetChatPlaylists :many
+SELECT id FROM playlists
+WHERE telegram_id = $1 AND deleted_at IS NULL
+ORDER BY topic_id;
+
+-- name: GetPlaylistTracks :many
+SELECT
+    t.id, t.title, t.authors, t.thumbnail, t.length, t.explicit,
+    COALESCE(s.user_id, 0)::bigint AS submitter_id,
+    COALESCE(s.submitter_name, '')::text AS submitter_name,
+    COALESCE(u.first_name, '')::text AS first_name,
+    COALESCE(u.last_name, '')::text AS last_name,
+    COALESCE(u.username, '')::text AS username
+FROM playlists pl
+         CROSS JOIN unnest(pl.tracks) WITH ORDINALITY AS pt(track_id, ord)
+         JOIN tracks t ON t.id = pt.track_id
+         LEFT JOIN track_submissions s ON s.playlist_id = pl.id AND s.track_id = t.id
+         LEFT JOIN users u ON u.id = s.user_id
+WHERE pl.id = $1
+ORDER BY pt.ord;
+
+-- name: GetOwnerSuccessor :one
+SELECT user_id FROM playlist_permissions
+WHERE playlist_id = $1 AND role = 'moderator'
+ORDER BY created_at, user_id
+LIMIT 1;
+
+-- name: GetGroupPlaylistConflict :one
+SELECT live.id FROM playlists deleted
+JOIN playlists live ON live.telegram_id = deleted.telegram_id AND live.topic_id = deleted.topic_id
+WHERE deleted.id = $1 AND deleted.telegram_id <> 0 AND live.deleted_at IS NULL AND live.id <> deleted.id
+LIMIT 1;
@@ -12,7 +12,10 @@ CREATE TABLE IF NOT EXISTS playlists (
     allowed_tracks TEXT[] DEFAULT '{}',
     count INTEGER GENERATED ALWAYS AS (COALESCE(array_length(tracks, 1), 0)) STORED,
     allowed_count INTEGER GENERATED ALWAYS AS (COALESCE(array_length(allowed_tracks, 1), 0)) STORED,
-    time INTEGER NOT NULL DEFAULT 0
+    time INTEGER NOT NULL DEFAULT 0,
+    deleted_at TIMESTAMPTZ,
+    language TEXT NOT NULL DEFAULT 'ru',
+    topic_id INTEGER NOT NULL DEFAULT 0
 );
 
 CREATE TABLE IF NOT EXISTS tracks (
@@ -25,16 +28,86 @@ CREATE TABLE IF NOT EXISTS tracks (
 );
 
 CREATE TABLE IF NOT EXISTS users (
-    id BIGINT NOT NULL PRIMARY KEY
+    id BIGINT NOT NULL PRIMARY KEY,
+    first_name TEXT NOT NULL DEFAULT '',
+    last_name TEXT NOT NULL DEFAULT '',
+    username TEXT NOT NULL DEFAULT '',
+    language_code TEXT NOT NULL DEFAULT '',
+    photo_url TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
 CREATE TABLE IF NOT EXISTS playlist_permissions (
     playlist_id TEXT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
     user_id BIGINT NOT NULL REFERENCES users(id),
     role playlist_role NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
     PRIMARY KEY (playlist_id, user_id)
 );
 
+CREATE TABLE IF NOT EXISTS playlist_mutes (
+    playlist_id TEXT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_by BIGINT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (playlist_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS playlist_overrides (
+    playlist_id TEXT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    role playlist_role NOT NULL,
+    capability TEXT NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    PRIMARY KEY (playlist_id, role, capability)
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id TEXT NOT NULL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_hash TEXT NOT NULL UNIQUE,
+    previous_hash TEXT,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id TEXT NOT NULL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    playlists TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+-- кто предложил трек в плейлист
+CREATE TABLE IF NOT EXISTS track_submissions (
+    playlist_id TEXT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    track_id TEXT NOT NULL REFERENCES tracks(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    submitter_name TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (playlist_id, track_id)
+);
+
+-- история модерации хранится и после удаления плейлиста
+CREATE TABLE IF NOT EXISTS moderation_log (
+    id BIGSERIAL PRIMARY KEY,
+    playlist_id TEXT NOT NULL,
+    actor_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    target_user_id BIGINT,
+    track_id TEXT,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
 CREATE OR REPLACE FUNCTION calculate_playlist_time(track_ids TEXT[])
     RETURNS INTEGER AS $$
 DECLARE
@@ -68,10 +141,31 @@ BEGIN
 END;
 $$ LANGUAGE plpgsql;
 
+CREATE OR REPLACE FUNCTION thumbnail_hash(url TEXT)
+    RETURNS TEXT AS $$
+    SELECT encode(sha256(convert_to(url, 'UTF8')), 'hex');
+$$ LANGUAGE sql IMMUTABLE;
+
 CREATE INDEX IF NOT EXISTS idx_tracks_id ON tracks (id);
 
 CREATE INDEX IF NOT EXISTS idx_playlists_tracks ON playlists USING GIN(tracks);
 
 CREATE INDEX IF NOT EXISTS idx_playlists_allowed_tracks ON playlists USING GIN(allowed_tracks);
 
-CREATE INDEX IF NOT EXISTS idx_permissions_user ON playlist_permissions (user_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_permissions_user ON playlist_permissions (user_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_single_owner ON playlist_permissions (playlist_id) WHERE role = 'owner';
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions (user_id);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_hash ON sessions (previous_hash);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user ON api_tokens (user_id);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_log_playlist ON moderation_log (playlist_id, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_track_submissions_user ON track_submissions (playlist_id, user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_tracks_thumbnail_hash ON tracks (thumbnail_hash(thumbnail));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_playlists_group_topic ON playlists (telegram_id, topic_id) WHERE telegram_id <> 0 AND deleted_at IS NULL;
\ No newline at end of file